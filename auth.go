@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// buildTimeAPIKey is an OpenAI key embedded with -ldflags. It can be pulled
+// out of the binary with strings, so it is only used as a last resort and
+// always warned about.
+var buildTimeAPIKey string
+
+// credentialsFile holds the keys stored by `revyu auth login`, next to the
+// user config
+const credentialsFile = "credentials.yaml"
+
+// providerEnv maps each provider to the environment variable holding its key
+var providerEnv = map[string]string{
+	"openai": "OPENAI_API_KEY",
+}
+
+// credentialsPath returns the file `revyu auth login` writes to
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %v", err)
+	}
+
+	return filepath.Join(dir, "revyu", credentialsFile), nil
+}
+
+// readCredentials returns the stored keys by provider. A missing file is
+// an empty set.
+func readCredentials() (map[string]string, error) {
+	creds := map[string]string{}
+	path, err := credentialsPath()
+	if err != nil {
+		return creds, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return creds, nil
+	}
+	if err != nil {
+		return creds, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, &creds); err != nil {
+		return creds, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	return creds, nil
+}
+
+// writeCredentials stores the keys readable by the current user only. The
+// file is removed once the last key is gone.
+func writeCredentials(creds map[string]string) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+
+	if len(creds) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", path, err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	data, err := yaml.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %v", err)
+	}
+	// The keys go to a new file created readable by the current user only,
+	// which then replaces the old one, so they are never written to a file
+	// with looser permissions
+	tmp, err := os.CreateTemp(filepath.Dir(path), credentialsFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// runCredentialHelper runs the configured helper through the shell and
+// returns the first line it prints. The provider is passed in
+// $REVYU_PROVIDER.
+func runCredentialHelper(helper, provider string) (string, error) {
+	shell, arg := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, arg = "cmd", "/C"
+	}
+
+	cmd := exec.Command(shell, arg, helper)
+	cmd.Env = append(os.Environ(), "REVYU_PROVIDER="+provider)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("credential helper %q failed: %v", helper, err)
+	}
+
+	key, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSpace(key), nil
+}
+
+// findAPIKey returns the key for the configured provider and where it came
+// from. The environment wins so CI can inject a key, then the credential
+// helper, then the file written by `revyu auth login`, and only then a key
+// embedded at build time.
+func findAPIKey(cfg Config) (key, source string, err error) {
+	provider := cfg.Provider
+	if name, ok := providerEnv[provider]; ok {
+		if key := strings.TrimSpace(os.Getenv(name)); key != "" {
+			return key, "$" + name, nil
+		}
+	}
+
+	if cfg.CredentialHelper != "" {
+		key, err := runCredentialHelper(cfg.CredentialHelper, provider)
+		if err != nil {
+			return "", "", err
+		}
+		if key != "" {
+			return key, "credential helper", nil
+		}
+	}
+
+	creds, err := readCredentials()
+	if err != nil {
+		return "", "", err
+	}
+	if key := creds[provider]; key != "" {
+		path, _ := credentialsPath()
+		return key, path, nil
+	}
+
+	if provider == "openai" && !checkEmpty(buildTimeAPIKey) {
+		return buildTimeAPIKey, "embedded at build time", nil
+	}
+
+	return "", "", nil
+}
+
+// warnEmbeddedKey tells the user that the binary carries an API key
+func warnEmbeddedKey() {
+	if checkEmpty(buildTimeAPIKey) {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, theme.Error.Render(theme.plain("⚠️  Warning: this binary has an API key embedded at build time.")))
+	fmt.Fprintln(os.Stderr, theme.Content.Render("Anyone with a copy of the binary can extract it. Rotate the key, rebuild"))
+	fmt.Fprintln(os.Stderr, theme.Content.Render("without -X main.buildTimeAPIKey and store it with: revyu auth login"))
+}
+
+// requireAPIKey looks up the API key for the configured provider. It is
+// only called once a command's arguments have been validated, so help and
+// usage errors never need a key. The lookup problem is printed to stderr when
+// no key is found.
+func requireAPIKey(cfg Config) (string, bool) {
+	warnEmbeddedKey()
+
+	apiKey, _, err := findAPIKey(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error reading credentials"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render(err.Error()))
+		return "", false
+	}
+
+	if checkEmpty(apiKey) {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(theme.plain(fmt.Sprintf("❌ Error: no API key found for %s", cfg.Provider))))
+		fmt.Fprintln(os.Stderr, theme.Content.Render("Please either:"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render("  1. Run: revyu auth login"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render("  2. Set the "+providerEnv[cfg.Provider]+" environment variable"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render("  3. Set credential_helper in your user config to a command that prints the key"))
+		return "", false
+	}
+
+	addLogSecret(apiKey)
+	return apiKey, true
+}
+
+// maskKey hides all but the last four characters of a secret
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return "****"
+	}
+
+	return "****" + key[len(key)-4:]
+}
+
+// readSecret reads a key from the terminal without echoing it, or the first
+// line of stdin when it is not a terminal
+func readSecret(prompt string) (string, error) {
+	if fd := os.Stdin.Fd(); term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read key: %v", err)
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read key from stdin: %v", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// printAuthUsage prints the help of `revyu auth`
+func printAuthUsage() {
+	fs := newFlagSet("auth")
+	fs.String("provider", "", "provider to log in to or out of (default from the config)")
+	printHelp("auth login|logout|status [--provider NAME]",
+		"Stores API keys in "+credentialsFile+" under the user config directory, readable\n"+
+			"only by you. login reads the key from the terminal or from stdin, so\n"+
+			"  echo $KEY | revyu auth login\n"+
+			"works in scripts. status shows which key each provider would use.",
+		fs)
+}
+
+// runAuth runs `revyu auth login|logout|status` and returns the exit code
+func runAuth(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		printAuthUsage()
+		return exitOK
+	}
+	if len(args) == 0 || (args[0] != "login" && args[0] != "logout" && args[0] != "status") {
+		printAuthUsage()
+		return exitUsage
+	}
+	action := args[0]
+
+	fs := newFlagSet("auth " + action)
+	provider := fs.String("provider", "", "")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return usageError(err, printAuthUsage)
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q", fs.Arg(0)), printAuthUsage)
+	}
+
+	settings := map[string]string{}
+	if *provider != "" {
+		settings["provider"] = *provider
+	}
+	cfg, _, _, ok := loadSettings(settings)
+	if !ok {
+		return exitError
+	}
+	if _, known := providerEnv[cfg.Provider]; !known {
+		return usageError(fmt.Errorf("unknown provider %q (supported: %s)", cfg.Provider, strings.Join(supportedProviders, ", ")), printAuthUsage)
+	}
+
+	switch action {
+	case "login":
+		return authLogin(cfg.Provider)
+	case "logout":
+		return authLogout(cfg.Provider)
+	}
+
+	return authStatus(cfg)
+}
+
+// authLogin stores a key for the provider
+func authLogin(provider string) int {
+	key, err := readSecret(fmt.Sprintf("Paste your %s API key: ", provider))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+	if key == "" {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: no key given"))
+		return exitError
+	}
+
+	creds, err := readCredentials()
+	if err == nil {
+		creds[provider] = key
+		err = writeCredentials(creds)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+
+	path, _ := credentialsPath()
+	fmt.Println(theme.Success.Render(fmt.Sprintf("Stored the %s key %s in %s", provider, maskKey(key), path)))
+	return exitOK
+}
+
+// authLogout removes the stored key for the provider
+func authLogout(provider string) int {
+	creds, err := readCredentials()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+	if _, ok := creds[provider]; !ok {
+		fmt.Println(theme.Subtitle.Render("No stored key for " + provider))
+		return exitOK
+	}
+
+	delete(creds, provider)
+	if err := writeCredentials(creds); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+
+	fmt.Println(theme.Success.Render("Removed the stored key for " + provider))
+	if name := providerEnv[provider]; os.Getenv(name) != "" {
+		fmt.Println(theme.Content.Render("$" + name + " is still set and will be used"))
+	}
+	return exitOK
+}
+
+// authStatus shows where the key for the provider would come from
+func authStatus(cfg Config) int {
+	warnEmbeddedKey()
+
+	key, source, err := findAPIKey(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+	if key == "" {
+		fmt.Println(theme.Error.Render(fmt.Sprintf("%s: not logged in, run revyu auth login", cfg.Provider)))
+		return exitError
+	}
+
+	fmt.Println(theme.Success.Render(fmt.Sprintf("%s: %s (%s)", cfg.Provider, maskKey(key), source)))
+
+	path, _ := credentialsPath()
+	if info, err := os.Stat(path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		fmt.Println(theme.Error.Render(fmt.Sprintf("%s is readable by other users, run: chmod 600 %s", path, path)))
+	}
+	return exitOK
+}
@@ -1,41 +1,23 @@
 # PowerShell build script for Windows
 
 $BinaryName = "revyu.exe"
-$EnvFile = ".env"
 
 Write-Host "Building Revyu for Windows..." -ForegroundColor Cyan
 
-# Load .env file if it exists
-if (Test-Path $EnvFile) {
-    Get-Content $EnvFile | ForEach-Object {
-        if ($_ -match '^([^#][^=]+)=(.*)$') {
-            $key = $matches[1].Trim()
-            $value = $matches[2].Trim()
-            [Environment]::SetEnvironmentVariable($key, $value, "Process")
-        }
-    }
-    Write-Host "Loaded .env file" -ForegroundColor Green
-} else {
-    Write-Host "Warning: .env file not found" -ForegroundColor Yellow
-    Write-Host "Building without embedded API key" -ForegroundColor Yellow
-    Write-Host "You'll need to set OPENAI_API_KEY environment variable or create .env file" -ForegroundColor Yellow
-}
-
-# Build with or without embedded API key
-$ApiKey = $env:OPENAI_API_KEY
+$Version = git describe --tags --always --dirty 2>$null
+if ([string]::IsNullOrEmpty($Version)) { $Version = "dev" }
 
-if ([string]::IsNullOrEmpty($ApiKey)) {
-    Write-Host "Building $BinaryName without embedded API key..." -ForegroundColor Yellow
-    go build -o $BinaryName
-} else {
-    Write-Host "Building $BinaryName with embedded OpenAI API key..." -ForegroundColor Green
-    go build -ldflags "-X main.buildTimeAPIKey=$ApiKey" -o $BinaryName
-}
+# API keys are never embedded: anyone with the binary could extract them.
+# Store yours with "revyu auth login" or set OPENAI_API_KEY instead.
+Write-Host "Building $BinaryName $Version..." -ForegroundColor Cyan
+go build -ldflags "-X main.version=$Version" -o $BinaryName
 
 if ($LASTEXITCODE -eq 0) {
     Write-Host "Build successful!" -ForegroundColor Green
     Write-Host "Binary created: .\$BinaryName" -ForegroundColor Green
     Write-Host ""
+    Write-Host "Store your API key once with: .\$BinaryName auth login" -ForegroundColor Cyan
+    Write-Host ""
     Write-Host "To install globally, you can:" -ForegroundColor Cyan
     Write-Host "  1. Run: make install" -ForegroundColor White
     Write-Host "  2. Or manually copy to a directory in your PATH" -ForegroundColor White
@@ -4,24 +4,12 @@
 OS_TYPE=$(uname -s)
 BINARY_NAME="revyu"
 
-# Load .env file if it exists
-if [ -f .env ]; then
-    export $(cat .env | grep -v '^#' | xargs)
-    echo "Loaded .env file"
-else
-    echo "Warning: .env file not found"
-    echo "Building without embedded API key"
-    echo "You'll need to set OPENAI_API_KEY environment variable or create .env file"
-fi
+VERSION=$(git describe --tags --always --dirty 2>/dev/null || echo dev)
 
-# Build with or without embedded API key
-if [ -z "$OPENAI_API_KEY" ]; then
-    echo "Building $BINARY_NAME without embedded API key..."
-    go build -o $BINARY_NAME
-else
-    echo "Building $BINARY_NAME with embedded OpenAI API key..."
-    go build -ldflags "-X main.buildTimeAPIKey=$OPENAI_API_KEY" -o $BINARY_NAME
-fi
+# API keys are never embedded: anyone with the binary could extract them.
+# Store yours with "revyu auth login" or set OPENAI_API_KEY instead.
+echo "Building $BINARY_NAME $VERSION..."
+go build -ldflags "-X main.version=$VERSION" -o $BINARY_NAME
 
 if [ $? -eq 0 ]; then
     echo "Build successful!"
@@ -39,6 +27,8 @@ if [ $? -eq 0 ]; then
         fi
     fi
     
+    echo ""
+    echo "Store your API key once with: ./$BINARY_NAME auth login"
     echo ""
     echo "To install globally, run:"
     if [ "$OS_TYPE" = "Darwin" ] || [ "$OS_TYPE" = "Linux" ]; then
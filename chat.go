@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Shortcuts that expand into canned follow-up questions
+var chatShortcuts = map[string]string{
+	"/fix":       "Suggest an alternative fix for this finding and explain the trade-offs compared to the original suggestion.",
+	"/challenge": "I think this finding may be wrong or not applicable. Re-examine the diff and either defend the finding with concrete evidence or retract it.",
+	"/explain":   "I don't understand this finding. Explain the problem in more detail, with an example of how it could go wrong.",
+}
+
+// itemContext describes a finding so the model knows what the user is asking about
+func itemContext(item ReviewItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The user is now asking about finding #%d (%s, severity %s): %s\n", item.number, item.category, item.severity, item.title)
+	if item.content != "" {
+		b.WriteString(item.content)
+		b.WriteString("\n")
+	}
+	for _, code := range item.codeBlocks {
+		b.WriteString("```\n")
+		b.WriteString(code)
+		b.WriteString("\n```\n")
+	}
+
+	return b.String()
+}
+
+// openChat shows the chat pane focused on an item, or on the whole diff when
+// itemIdx is -1. Switching focus is recorded in the conversation so earlier
+// answers keep their context.
+func (m *model) openChat(itemIdx int) {
+	m.chatOpen = true
+	m.chatErr = nil
+	if itemIdx == m.chatFocus && len(m.chatLog) > 0 {
+		return
+	}
+
+	m.chatFocus = itemIdx
+	if itemIdx >= 0 {
+		m.chatLog = append(m.chatLog, Message{Role: "system", Content: itemContext(m.items[itemIdx])})
+	} else if len(m.chatLog) > 0 {
+		m.chatLog = append(m.chatLog, Message{Role: "system", Content: "The user is now asking about the diff as a whole."})
+	}
+}
+
+// chatMessages builds the full conversation sent to the model: the original
+// review request and answer followed by the follow-up history
+func (m model) chatMessages() []Message {
+	messages := []Message{
+		{Role: "user", Content: buildReviewPrompt(m.diff, seriesMessage(m.patches), m.opts.Profile)},
+		{Role: "assistant", Content: m.review},
+	}
+
+	return append(messages, m.chatLog...)
+}
+
+func (m model) askChat() tea.Cmd {
+	messages := m.chatMessages()
+	return func() tea.Msg {
+		answer, _, err := chatCompletion(m.apiKey, m.opts.Model, messages)
+		return chatMsg{answer: answer, err: err}
+	}
+}
+
+// updateChat handles typing in the chat pane
+func (m model) updateChat(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		cmd := m.quit()
+		return m, cmd
+	case key.Matches(msg, m.keys.Back):
+		m.chatOpen = false
+	case m.chatInput == "" && key.Matches(msg, m.keys.Help):
+		m.showHelp = true
+	case key.Matches(msg, m.keys.Send):
+		question := strings.TrimSpace(m.chatInput)
+		if question == "" || m.chatBusy {
+			return m, nil
+		}
+		if expanded, ok := chatShortcuts[question]; ok {
+			question = expanded
+		}
+		m.chatLog = append(m.chatLog, Message{Role: "user", Content: question})
+		m.chatInput = ""
+		m.chatBusy = true
+		m.chatErr = nil
+		return m, m.askChat()
+	case msg.Type == tea.KeyBackspace:
+		if len(m.chatInput) > 0 {
+			runes := []rune(m.chatInput)
+			m.chatInput = string(runes[:len(runes)-1])
+		}
+	case msg.Type == tea.KeySpace:
+		m.chatInput += " "
+	case msg.Type == tea.KeyRunes:
+		m.chatInput += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// renderChat writes the conversation transcript and the input line
+func (m model) renderChat(s *strings.Builder, maxWidth int) {
+	focus := "the whole diff"
+	if m.chatFocus >= 0 && m.chatFocus < len(m.items) {
+		focus = fmt.Sprintf("#%d %s", m.items[m.chatFocus].number, m.items[m.chatFocus].title)
+	}
+	s.WriteString(theme.Subtitle.Render("Chatting about " + focus))
+	s.WriteString("\n")
+
+	for _, message := range m.chatLog {
+		switch message.Role {
+		case "system":
+			s.WriteString(theme.Separator.Render(strings.Repeat("─", maxWidth)))
+			s.WriteString("\n")
+		case "user":
+			s.WriteString(theme.ChatUser.Render("You:"))
+			s.WriteString("\n")
+			s.WriteString(theme.Content.Render(wrapText(message.Content, maxWidth-4)))
+			s.WriteString("\n\n")
+		case "assistant":
+			s.WriteString(theme.ChatBot.Render("Revyu:"))
+			s.WriteString("\n")
+			s.WriteString(formatMarkdown(message.Content, maxWidth))
+			s.WriteString("\n")
+		}
+	}
+
+	if m.chatBusy {
+		s.WriteString(m.spinner.View())
+		s.WriteString(" Thinking...\n")
+	}
+	if m.chatErr != nil {
+		s.WriteString(theme.Error.Render(m.chatErr.Error()))
+		s.WriteString("\n")
+	}
+
+	s.WriteString(theme.Content.Render("> " + m.chatInput + "█"))
+	s.WriteString("\n")
+}
+
+// saveTranscript writes the review and the follow-up conversation next to the
+// repository's git metadata so it survives the session
+func (m model) saveTranscript() error {
+	dir, err := gitDir()
+	if err != nil {
+		return err
+	}
+
+	dir = filepath.Join(dir, "revyu")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Review of %s\n\n", m.filePath)
+	b.WriteString(m.review)
+	b.WriteString("\n\n# Follow-up\n\n")
+	for _, message := range m.chatLog {
+		switch message.Role {
+		case "system":
+			fmt.Fprintf(&b, "---\n\n> %s\n\n", strings.ReplaceAll(strings.TrimSpace(message.Content), "\n", "\n> "))
+		case "user":
+			fmt.Fprintf(&b, "**You:** %s\n\n", message.Content)
+		case "assistant":
+			fmt.Fprintf(&b, "**Revyu:**\n\n%s\n\n", message.Content)
+		}
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("transcript-%s.md", diffHash(m.diff)[:12]))
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to save transcript: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Process exit codes. exitFindings is only used when --fail-on or
+// --max-findings is given, so scripts can tell a failed gate from a failed run.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitFindings = 3
+)
+
+// plainWidth is the line width of the non-interactive output
+const plainWidth = 100
+
+// cliOptions holds the parsed command line
+type cliOptions struct {
+	target string
+	staged bool
+	noTUI  bool
+	dryRun bool
+	format string
+
+	// patches holds the targets when they are patch files or "-" for stdin
+	patches []string
+
+	failOn      Severity
+	maxFindings int
+
+	output string
+
+	// settings holds the config values given as flags, keyed by setting
+	settings map[string]string
+}
+
+// settingFlags are the flags that override a config setting of the same name
+var settingFlags = map[string]string{
+	"provider": "provider",
+	"model":    "model",
+	"profile":  "profile",
+	"format":   "format",
+	"theme":    "theme",
+	"debug":    "debug",
+	"log-file": "log_file",
+}
+
+// outputFormats lists the values accepted by --format
+var outputFormats = []string{"text", "json", "sarif", "github"}
+
+// errHelp is returned by the argument parsers when help was requested
+var errHelp = errors.New("help requested")
+
+// reviewFlags defines the flags of `revyu review` on a new flag set. The
+// --fail-on value is stored in failOn for parseArgs to validate.
+func reviewFlags(cli *cliOptions, failOn *string) *flag.FlagSet {
+	fs := newFlagSet("review")
+	fs.BoolVar(&cli.noTUI, "no-tui", false, "print the review as plain text instead of starting the TUI")
+	fs.BoolVar(&cli.staged, "staged", false, "review the staged changes instead of the working tree")
+	fs.BoolVar(&cli.dryRun, "dry-run", false, "print the prompt and what would be sent without calling the provider")
+	fs.String("format", "", "`format` for non-interactive runs: "+strings.Join(outputFormats, ", "))
+	fs.String("provider", "", "API provider to review with")
+	fs.String("model", "", "model to review with")
+	fs.String("profile", "", "review profile")
+	fs.String("theme", "", "colour theme")
+	fs.StringVar(failOn, "fail-on", "", "exit with status 3 on findings at or above this `severity`")
+	fs.IntVar(&cli.maxFindings, "max-findings", -1, "exit with status 3 when there are more than `N` findings")
+	fs.StringVar(&cli.output, "output", "", "also write a Markdown or HTML report to this `file`")
+	debugFlags(fs)
+	return fs
+}
+
+// debugFlags adds --debug and --log-file, shared by every command that
+// talks to a provider
+func debugFlags(fs *flag.FlagSet) {
+	fs.Bool("debug", false, "log git commands, the prompt, requests, responses and timings")
+	fs.String("log-file", "", "write the debug log to this `file` instead of stderr")
+}
+
+// flagSettings returns the config settings given as flags, keyed by setting
+func flagSettings(fs *flag.FlagSet) map[string]string {
+	settings := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		if key, ok := settingFlags[f.Name]; ok {
+			settings[key] = f.Value.String()
+		}
+	})
+
+	return settings
+}
+
+// printReviewUsage prints the help of `revyu review`
+func printReviewUsage() {
+	var failOn string
+	printHelp("review [flags] <file|.|-|patch...>",
+		"Reviews the git diff of a file, or of all tracked files with \".\". The review\n"+
+			"opens in the TUI when stdout is a terminal and is printed otherwise. The\n"+
+			"review command name may be left out: revyu . is the same as revyu review .\n\n"+
+			"A unified diff can be piped in with -, as in git diff main | revyu -. Files\n"+
+			"ending in .patch, .diff, .mbox or .eml are read as patches; a git\n"+
+			"format-patch series is reviewed one patch at a time with its commit message.",
+		reviewFlags(&cliOptions{}, &failOn))
+}
+
+// parseArgs parses the arguments of `revyu review`. Flags may appear before
+// or after the target, so `revyu . --no-tui` and `revyu --no-tui .` are
+// equivalent.
+func parseArgs(args []string) (cliOptions, error) {
+	var (
+		cli    cliOptions
+		failOn string
+	)
+	fs := reviewFlags(&cli, &failOn)
+
+	var positional []string
+	for {
+		if err := parseFlags(fs, args); err != nil {
+			return cli, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) == 0 {
+		return cli, fmt.Errorf("missing target, pass a file, . for all tracked files or - for a diff on stdin")
+	}
+	if i := slices.IndexFunc(positional, isPatchTarget); i >= 0 {
+		// Several patch files are reviewed as one series
+		for _, target := range positional {
+			if !isPatchTarget(target) {
+				return cli, fmt.Errorf("cannot mix the patch %s with the target %q", positional[i], target)
+			}
+		}
+		if slices.Contains(positional, "-") && len(positional) > 1 {
+			return cli, fmt.Errorf("- reads a diff from stdin and cannot be combined with other targets")
+		}
+		if cli.staged {
+			return cli, fmt.Errorf("--staged cannot be used with patches")
+		}
+		cli.patches = positional
+	} else if len(positional) > 1 {
+		return cli, fmt.Errorf("expected a single target, got %d", len(positional))
+	}
+	cli.target = strings.Join(positional, " ")
+
+	cli.settings = flagSettings(fs)
+	if format, ok := cli.settings["format"]; ok && !slices.Contains(outputFormats, format) {
+		return cli, fmt.Errorf("unknown format %q (supported: %s)", format, strings.Join(outputFormats, ", "))
+	}
+
+	if failOn != "" {
+		severity, err := parseSeverity(failOn)
+		if err != nil {
+			return cli, err
+		}
+		cli.failOn = severity
+	}
+	if cli.maxFindings < -1 {
+		return cli, fmt.Errorf("--max-findings must not be negative")
+	}
+	if cli.output != "" {
+		if _, err := reportFormat(cli.output); err != nil {
+			return cli, err
+		}
+	}
+
+	return cli, nil
+}
+
+// interactive reports whether the TUI can be used: stdout must be a terminal
+// and neither a machine-readable format nor a CI gate was requested
+func (cli cliOptions) interactive() bool {
+	if cli.noTUI || cli.format != "text" || cli.failOn != "" || cli.maxFindings >= 0 {
+		return false
+	}
+
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// runPlain reviews the diff synchronously and prints it in the requested
+// format. It returns the process exit code.
+func runPlain(apiKey string, cli cliOptions, opts reviewOptions, diff string, patches []patch) int {
+	run, err := runSeriesReview(apiKey, cli.target, diff, patches, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error getting review: "+err.Error())
+		return exitError
+	}
+	run.base, run.head = diffRevisions(cli.target, cli.staged, cli.patches != nil)
+
+	return printRun(cli, run)
+}
+
+// printRun writes a finished run to stdout in the requested format
+func printRun(cli cliOptions, run reviewRun) int {
+	var (
+		out string
+		err error
+	)
+	switch cli.format {
+	case "json":
+		out, err = jsonReport(run)
+	case "sarif":
+		out, err = sarifReport(run)
+	case "github":
+		out = githubAnnotations(run)
+		err = writeStepSummary(run)
+	default:
+		out = theme.plain(formatMarkdown(run.review, plainWidth))
+	}
+	fmt.Print(out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error writing report: "+err.Error())
+		return exitError
+	}
+
+	if cli.output != "" {
+		// Carry over checklist progress from an earlier interactive session
+		if state, ok := loadSession(run.target, run.diff, run.opts); ok {
+			associate(run.items, state.Items)
+		}
+		if err := writeReport(cli.output, run); err != nil {
+			fmt.Fprintln(os.Stderr, "Error writing report: "+err.Error())
+			return exitError
+		}
+	}
+
+	if reasons := gateFailures(cli, run.items); len(reasons) > 0 {
+		fmt.Fprintln(os.Stderr, "revyu: failing: "+strings.Join(reasons, "; "))
+		return exitFindings
+	}
+
+	return exitOK
+}
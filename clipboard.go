@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMsg is sent after text was handed to the terminal clipboard
+type clipboardMsg struct {
+	what string
+	err  error
+}
+
+// copyToClipboard sends text to the system clipboard with an OSC 52 escape
+// sequence, which terminals honour over SSH as well. tmux and screen need the
+// sequence wrapped so they pass it through to the outer terminal.
+func copyToClipboard(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+
+		_, err := seq.WriteTo(os.Stderr)
+		return clipboardMsg{what: what, err: err}
+	}
+}
+
+// itemText renders a finding as plain text: location and explanation
+func itemText(item ReviewItem) string {
+	title := strings.TrimSpace(strings.ReplaceAll(item.title, "📄", ""))
+	text := fmt.Sprintf("#%d [%s] %s", item.number, item.severity, title)
+	if item.content != "" {
+		text += "\n" + item.content
+	}
+
+	return text
+}
+
+// suggestionText returns the item's suggested code, which the review puts in
+// the last code block of a finding
+func suggestionText(item ReviewItem) string {
+	if len(item.codeBlocks) == 0 {
+		return ""
+	}
+
+	return item.codeBlocks[len(item.codeBlocks)-1]
+}
+
+// checklistMarkdown renders the non-dismissed findings as a Markdown task list
+func checklistMarkdown(target string, items []ReviewItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Revyu review: %s\n\n", target)
+
+	for _, item := range items {
+		if item.dismissed {
+			continue
+		}
+
+		box := " "
+		if item.checked {
+			box = "x"
+		}
+		location := strings.TrimSpace(strings.ReplaceAll(item.title, "📄", ""))
+		fmt.Fprintf(&b, "- [%s] **#%d %s** `%s`", box, item.number, item.severity, location)
+		if item.content != "" {
+			fmt.Fprintf(&b, " - %s", item.content)
+		}
+		b.WriteString("\n")
+
+		for _, code := range item.codeBlocks {
+			b.WriteString("  ```\n")
+			for _, line := range strings.Split(code, "\n") {
+				b.WriteString("  " + line + "\n")
+			}
+			b.WriteString("  ```\n")
+		}
+	}
+
+	return b.String()
+}
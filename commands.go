@@ -0,0 +1,231 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// command is one `revyu <name>` subcommand. Each command parses and
+// validates its own flags before it loads credentials.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) int
+}
+
+// commands lists the subcommands in the order they appear in the help. It is
+// filled in init because the help command refers back to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"review", "review [flags] <file|.|-|patch...>", "Review the git diff of a file, all tracked files with ., stdin or patches", runReviewCommand},
+		{"github", "github review --pr N [flags]", "Review a GitHub pull request and post the findings", runGitHub},
+		{"gitlab", "gitlab review --mr N [flags]", "Review a GitLab merge request and post the findings", runGitLab},
+		{"config", "config show [--origin]", "Show the merged configuration", runConfig},
+		{"init", "init [--force]", "Create a " + repoConfigFile + " for this repository", runInit},
+		{"auth", "auth login|logout|status", "Store, remove or show the API key of a provider", runAuth},
+		{"hook", "hook install|uninstall [--force]", "Review staged changes from a git pre-commit hook", runHook},
+		{"history", "history [--limit N]", "List the saved review sessions of this repository", runHistory},
+		{"cache", "cache info|clear", "Show or remove the sessions and transcripts revyu keeps", runCache},
+		{"version", "version", "Print the version", runVersion},
+		{"help", "help [command]", "Show help for a command", runHelp},
+	}
+}
+
+// findCommand returns the subcommand with the given name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+// dispatch runs the subcommand named by the first argument and returns the
+// exit code. Anything that is not a subcommand is reviewed, so `revyu .`
+// stays short for `revyu review .`.
+func dispatch(args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitUsage
+	}
+
+	if isHelp(args[0]) {
+		printUsage()
+		return exitOK
+	}
+	if args[0] == "--version" || args[0] == "-version" {
+		return runVersion(nil)
+	}
+
+	if cmd, ok := findCommand(args[0]); ok {
+		return cmd.run(args[1:])
+	}
+
+	return runReviewCommand(args)
+}
+
+// isHelp reports whether an argument asks for help
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// newFlagSet returns a flag set that reports errors to the caller instead of
+// printing them
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("revyu "+name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags parses a command's flags and returns errHelp for -h and --help
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return errHelp
+		}
+		return err
+	}
+
+	return nil
+}
+
+// usageError reports a parse error and returns the exit code for it. Help
+// requests print the help and succeed.
+func usageError(err error, help func()) int {
+	if errors.Is(err, errHelp) {
+		help()
+		return exitOK
+	}
+
+	fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+	fmt.Fprintln(os.Stderr, theme.Content.Render("Run with --help for usage."))
+	return exitUsage
+}
+
+// printHelp prints the synopsis, description and flags of a command
+func printHelp(usage, description string, fs *flag.FlagSet) {
+	fmt.Println(theme.Subtitle.Render("Usage:"))
+	fmt.Println(theme.Content.Render("  revyu " + usage))
+	fmt.Println()
+	for _, line := range strings.Split(description, "\n") {
+		fmt.Println(theme.Content.Render(line))
+	}
+
+	if fs == nil {
+		return
+	}
+	var flags []string
+	fs.VisitAll(func(f *flag.Flag) {
+		kind, text := flag.UnquoteUsage(f)
+		name := "--" + f.Name
+		if kind != "" {
+			name += " " + kind
+		}
+		switch f.DefValue {
+		case "", "false", "0", "-1":
+		default:
+			text += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		flags = append(flags, fmt.Sprintf("  %-22s %s", name, text))
+	})
+	if len(flags) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(theme.Subtitle.Render("Flags:"))
+	for _, line := range flags {
+		fmt.Println(theme.Content.Render(line))
+	}
+}
+
+// printUsage prints the top-level help
+func printUsage() {
+	fmt.Println(theme.Title.Render("Revyu - AI-Powered Code Review TESTING"))
+	fmt.Println()
+	fmt.Println(theme.Subtitle.Render("Usage:"))
+	fmt.Println(theme.Content.Render("  revyu <command> [flags]"))
+	fmt.Println(theme.Content.Render("  revyu <file|.|-> [flags] - Short for revyu review"))
+	fmt.Println()
+	fmt.Println(theme.Subtitle.Render("Commands:"))
+	for _, cmd := range commands {
+		fmt.Println(theme.Content.Render(fmt.Sprintf("  %-34s - %s", cmd.usage, cmd.summary)))
+	}
+	fmt.Println()
+	fmt.Println(theme.Content.Render("Run revyu <command> --help for the flags of a command."))
+}
+
+// runHelp runs `revyu help [command]`
+func runHelp(args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitOK
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("Error: unknown command %q", args[0])))
+		return exitUsage
+	}
+	if cmd.name == "help" {
+		printUsage()
+		return exitOK
+	}
+
+	return cmd.run([]string{"--help"})
+}
+
+// runVersion runs `revyu version`
+func runVersion(args []string) int {
+	fs := newFlagSet("version")
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(err, func() { printHelp("version", "Prints the version of revyu.", fs) })
+	}
+
+	fmt.Printf("revyu %s (%s, %s/%s)\n", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return exitOK
+}
+
+// loadSettings loads the layered configuration with the given flag
+// overrides and installs the theme. Problems are printed to stderr and
+// reported with ok set to false.
+func loadSettings(flags map[string]string) (cfg Config, origins configOrigins, keys keyMap, ok bool) {
+	cfg, origins, err := loadConfig(flags)
+	if err == nil {
+		err = cfg.apply()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error loading config"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render(err.Error()))
+		return cfg, origins, keys, false
+	}
+
+	theme, err = resolveTheme(cfg.Theme, cfg.Themes, cfg.ASCII)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error loading theme"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render(err.Error()))
+		return cfg, origins, keys, false
+	}
+
+	keys, err = newKeyMap(cfg.Keys)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error loading key bindings"))
+		fmt.Fprintln(os.Stderr, theme.Content.Render(err.Error()))
+		return cfg, origins, keys, false
+	}
+
+	return cfg, origins, keys, true
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// repoConfigFile is the repo-level config, read from the repository root
+const repoConfigFile = ".revyu.yaml"
+
+// Config holds the settings merged from every configuration layer
+type Config struct {
+	Provider       string            `yaml:"provider" toml:"provider"`
+	Model          string            `yaml:"model" toml:"model"`
+	Profile        string            `yaml:"profile" toml:"profile"`
+	PromptTemplate string            `yaml:"prompt_template" toml:"prompt_template"`
+	Profiles       map[string]string `yaml:"profiles" toml:"profiles"`
+	Ignore         []string          `yaml:"ignore" toml:"ignore"`
+	Redact         bool              `yaml:"redact" toml:"redact"`
+	Format         string            `yaml:"format" toml:"format"`
+
+	Keys   map[string][]string `yaml:"keys" toml:"keys"`
+	Theme  string              `yaml:"theme" toml:"theme"`
+	ASCII  bool                `yaml:"ascii" toml:"ascii"`
+	Themes map[string]Palette  `yaml:"themes" toml:"themes"`
+
+	DisableMouse bool `yaml:"disable_mouse" toml:"disable_mouse"`
+	ConfirmQuit  bool `yaml:"confirm_quit" toml:"confirm_quit"`
+
+	GitHubAPI string `yaml:"github_api_url" toml:"github_api_url"`
+	GitLabURL string `yaml:"gitlab_url" toml:"gitlab_url"`
+
+	CredentialHelper string `yaml:"credential_helper" toml:"credential_helper"`
+
+	Debug   bool   `yaml:"debug" toml:"debug"`
+	LogFile string `yaml:"log_file" toml:"log_file"`
+}
+
+// configOrigins records which layer set each config key. Keys of map
+// settings are recorded per entry, such as "keys.toggle".
+type configOrigins map[string]string
+
+// defaultConfig is the bottom layer
+func defaultConfig() Config {
+	opts := defaultReviewOptions()
+	return Config{
+		Provider: opts.Provider,
+		Model:    opts.Model,
+		Profile:  opts.Profile,
+		Redact:   true,
+		Format:   "text",
+		Theme:    "auto",
+	}
+}
+
+// mapKeys are the config keys whose values are maps merged entry by entry
+var mapKeys = []string{"keys", "themes", "profiles"}
+
+// userConfigPaths returns the candidate user config files in the order they
+// are tried; the first one that exists is used
+func userConfigPaths() ([]string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate config directory: %v", err)
+	}
+
+	dir = filepath.Join(dir, "revyu")
+	return []string{
+		filepath.Join(dir, "config.yaml"),
+		filepath.Join(dir, "config.yml"),
+		filepath.Join(dir, "config.toml"),
+	}, nil
+}
+
+// repoConfigPath returns the repo config file at the repository root, or in
+// the working directory outside a repository
+func repoConfigPath() string {
+	if root, err := gitToplevel(); err == nil {
+		return filepath.Join(root, repoConfigFile)
+	}
+
+	return repoConfigFile
+}
+
+// decodeLayer reads one config file on top of cfg and records the keys it
+// set. A missing file is skipped.
+func decodeLayer(cfg *Config, origins configOrigins, file string) (bool, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %v", file, err)
+	}
+
+	present := map[string]any{}
+	if strings.EqualFold(filepath.Ext(file), ".toml") {
+		if _, err := toml.Decode(string(data), cfg); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		if _, err := toml.Decode(string(data), &present); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		if err := yaml.Unmarshal(data, &present); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+	}
+
+	for key, value := range present {
+		if entries, ok := value.(map[string]any); ok && slices.Contains(mapKeys, key) {
+			for entry := range entries {
+				origins[key+"."+entry] = file
+			}
+			continue
+		}
+		origins[key] = file
+	}
+
+	return true, nil
+}
+
+// configField finds the settable Config field for a key
+func configField(cfg *Config, key string) (reflect.Value, bool) {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("yaml") == key {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setConfigValue sets a scalar or list setting from a string, as given in
+// an environment variable or on the command line. Lists are comma-separated.
+func setConfigValue(cfg *Config, key, value string) error {
+	field, ok := configField(cfg, key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", key, value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%s can only be set in a config file", key)
+	}
+
+	return nil
+}
+
+// envName returns the environment variable that overrides a config key
+func envName(key string) string {
+	return "REVYU_" + strings.ToUpper(key)
+}
+
+// configKeys lists the top-level config keys in declaration order
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, t.Field(i).Tag.Get("yaml"))
+	}
+
+	return keys
+}
+
+// userOnlyKeys cannot be set by a repository's .revyu.yaml. A cloned
+// repository must not be able to run commands on your machine, send your
+// GitHub or GitLab token to another host, write logs of your code to a path
+// of its choosing or turn off redaction to send its secrets to the provider.
+var userOnlyKeys = []string{"credential_helper", "github_api_url", "gitlab_url", "debug", "log_file", "redact"}
+
+// loadConfig merges the configuration layers: built-in defaults, the user
+// config file (YAML or TOML), the repo's .revyu.yaml, REVYU_* environment
+// variables and finally the command line flags in flags, keyed by setting.
+func loadConfig(flags map[string]string) (Config, configOrigins, error) {
+	cfg := defaultConfig()
+	origins := configOrigins{}
+	for _, key := range configKeys() {
+		origins[key] = "default"
+	}
+
+	paths, err := userConfigPaths()
+	if err != nil {
+		return cfg, origins, err
+	}
+	for _, file := range paths {
+		found, err := decodeLayer(&cfg, origins, file)
+		if err != nil {
+			return cfg, origins, err
+		}
+		if found {
+			break
+		}
+	}
+
+	repoFile := repoConfigPath()
+	if _, err := decodeLayer(&cfg, origins, repoFile); err != nil {
+		return cfg, origins, err
+	}
+	for _, key := range userOnlyKeys {
+		if origins[key] == repoFile {
+			return cfg, origins, fmt.Errorf("%s: %s can only be set in the user config", repoFile, key)
+		}
+	}
+
+	for _, key := range configKeys() {
+		if slices.Contains(mapKeys, key) {
+			continue
+		}
+		if value, ok := os.LookupEnv(envName(key)); ok {
+			if err := setConfigValue(&cfg, key, value); err != nil {
+				return cfg, origins, fmt.Errorf("%s: %v", envName(key), err)
+			}
+			origins[key] = "env " + envName(key)
+		}
+	}
+
+	for key, value := range flags {
+		if err := setConfigValue(&cfg, key, value); err != nil {
+			return cfg, origins, err
+		}
+		origins[key] = "flag --" + strings.ReplaceAll(key, "_", "-")
+	}
+
+	return cfg, origins, cfg.validate()
+}
+
+// validate checks the merged settings that are not checked elsewhere
+func (c Config) validate() error {
+	if !slices.Contains(outputFormats, c.Format) {
+		return fmt.Errorf("unknown format %q (supported: %s)", c.Format, strings.Join(outputFormats, ", "))
+	}
+	for _, pattern := range c.Ignore {
+		if _, err := path.Match(strings.TrimPrefix(pattern, "**/"), ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %v", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// apply installs the settings that live in package state: custom review
+// profiles and the prompt template
+func (c Config) apply() error {
+	for name, instructions := range c.Profiles {
+		reviewProfiles[name] = instructions
+	}
+
+	if c.PromptTemplate != "" {
+		return setPromptTemplate(c.PromptTemplate)
+	}
+
+	return nil
+}
+
+// reviewOptions returns the provider, model and profile to review with
+func (c Config) reviewOptions() reviewOptions {
+	return reviewOptions{Provider: c.Provider, Model: c.Model, Profile: c.Profile}
+}
+
+// describeValue formats a setting for `revyu config show`
+func describeValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		s := v.String()
+		if first, _, multiline := strings.Cut(s, "\n"); multiline {
+			return strconv.Quote(first + " …")
+		}
+		return strconv.Quote(s)
+	case reflect.Slice:
+		items := []string{}
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, strconv.Quote(fmt.Sprint(v.Index(i).Interface())))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		return "custom palette"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// showConfig renders the merged configuration, one setting per line, with
+// the layer each value came from when withOrigin is set
+func showConfig(cfg Config, origins configOrigins, withOrigin bool) string {
+	var b strings.Builder
+	line := func(key, value string) {
+		if withOrigin {
+			fmt.Fprintf(&b, "%-32s # %s\n", key+": "+value, origins[key])
+		} else {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+
+	for _, key := range configKeys() {
+		field, _ := configField(&cfg, key)
+		if field.Kind() != reflect.Map {
+			line(key, describeValue(field))
+			continue
+		}
+
+		entries := []string{}
+		for _, k := range field.MapKeys() {
+			entries = append(entries, k.String())
+		}
+		sort.Strings(entries)
+		if len(entries) == 0 {
+			line(key, "{}")
+		}
+		for _, entry := range entries {
+			line(key+"."+entry, describeValue(field.MapIndex(reflect.ValueOf(entry))))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRepoConfigCannotSetUserOnlyKeys(t *testing.T) {
+	root := initTestRepo(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		content string
+		refused string
+	}{
+		{"model: gpt-4o-mini\n", ""},
+		{"redact: false\n", "redact"},
+		{"log_file: /tmp/code.log\n", "log_file"},
+	}
+
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(root, repoConfigFile), []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := loadConfig(nil)
+		switch {
+		case tt.refused == "" && err != nil:
+			t.Errorf("%q: loadConfig: %v", tt.content, err)
+		case tt.refused != "" && (err == nil || !strings.Contains(err.Error(), tt.refused)):
+			t.Errorf("%q: loadConfig error = %v, want %s refused", tt.content, err, tt.refused)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// repoConfigTemplate is written by `revyu init`
+const repoConfigTemplate = `# revyu settings for this repository. They override your user config
+# (~/.config/revyu/config.yaml) and are overridden by REVYU_* environment
+# variables and command line flags. Run "revyu config show --origin" to see
+# where each value comes from.
+
+# provider: openai
+# model: gpt-4o
+# profile: default
+
+# Files left out of the review. Patterns without a slash match file names
+# anywhere; "dir/" matches everything below a directory.
+ignore:
+  - "*.lock"
+  - "go.sum"
+  - "package-lock.json"
+
+# Extra review profiles, selected with profile or --profile
+# profiles:
+#   api: Focus on backwards compatibility of the public API.
+
+# Replace the review prompt. {{.Focus}} is the profile's instructions and
+# {{.Diff}} the diff; keep the section headings so findings can be parsed.
+# prompt_template: |
+#   ...
+
+# format: text
+`
+
+// printConfigUsage prints the help of `revyu config`
+func printConfigUsage(fs *flag.FlagSet) {
+	printHelp("config show [--origin]",
+		"Shows the configuration merged from the defaults, your user config, the\n"+
+			"repository's "+repoConfigFile+" and REVYU_* environment variables.",
+		fs)
+}
+
+// runConfig runs `revyu config <command>` and returns the exit code
+func runConfig(args []string) int {
+	fs := newFlagSet("config show")
+	withOrigin := fs.Bool("origin", false, "show where each value came from")
+	usage := func() { printConfigUsage(fs) }
+
+	if len(args) > 0 && isHelp(args[0]) {
+		usage()
+		return exitOK
+	}
+	if len(args) == 0 || args[0] != "show" {
+		usage()
+		return exitUsage
+	}
+
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return usageError(err, usage)
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q", fs.Arg(0)), usage)
+	}
+
+	cfg, origins, _, ok := loadSettings(nil)
+	if !ok {
+		return exitError
+	}
+
+	fmt.Print(showConfig(cfg, origins, *withOrigin))
+	return exitOK
+}
+
+// runInit runs `revyu init`, which scaffolds the repo config
+func runInit(args []string) int {
+	fs := newFlagSet("init")
+	force := fs.Bool("force", false, "overwrite an existing file")
+	usage := func() {
+		printHelp("init [--force]", "Creates "+repoConfigFile+" at the repository root.", fs)
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(err, usage)
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q", fs.Arg(0)), usage)
+	}
+
+	path := repoConfigPath()
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(path+" already exists, use --force to overwrite it"))
+		return exitError
+	}
+
+	if err := os.WriteFile(path, []byte(repoConfigTemplate), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("failed to write %s: %v", path, err)))
+		return exitError
+	}
+
+	fmt.Println(theme.Success.Render("Created " + path))
+	return exitOK
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// dryRun describes what a review would send, printed by --dry-run instead
+// of calling the provider
+type dryRun struct {
+	Provider        string         `json:"provider"`
+	Model           string         `json:"model"`
+	Profile         string         `json:"profile"`
+	Endpoint        string         `json:"endpoint"`
+	PromptChars     int            `json:"prompt_chars"`
+	EstimatedTokens int            `json:"estimated_tokens"`
+	Requests        int            `json:"requests"`
+	Included        []string       `json:"included"`
+	Excluded        []string       `json:"excluded"`
+	Redacted        map[string]int `json:"redacted"`
+	Prompt          string         `json:"prompt"`
+}
+
+// estimateTokens approximates the prompt size in tokens. GPT tokenizers
+// average about four characters per token on English text and code.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// newDryRun renders the prompt exactly as a review would send it. A patch
+// series is reviewed with one request per patch, so its prompts are listed
+// one after the other.
+func newDryRun(opts reviewOptions, prepared preparedDiff, patches []patch) dryRun {
+	d := dryRun{
+		Provider: opts.Provider,
+		Model:    opts.Model,
+		Profile:  opts.Profile,
+		Endpoint: openAIEndpoint,
+		Included: prepared.included,
+		Excluded: prepared.excluded,
+		Redacted: prepared.redacted,
+	}
+	switch {
+	case len(patches) > 1:
+		prompts := make([]string, len(patches))
+		for i, p := range patches {
+			prompts[i] = fmt.Sprintf("----- patch %s -----\n%s",
+				patchTitle(p, i, len(patches)), buildReviewPrompt(p.diff, p.message, opts.Profile))
+		}
+		d.Prompt = strings.Join(prompts, "\n")
+		d.Requests = len(patches)
+	case len(patches) == 1:
+		d.Prompt = buildReviewPrompt(patches[0].diff, patches[0].message, opts.Profile)
+		d.Requests = 1
+	case strings.TrimSpace(prepared.diff) != "":
+		d.Prompt = buildReviewPrompt(prepared.diff, "", opts.Profile)
+		d.Requests = 1
+	}
+	d.PromptChars = len(d.Prompt)
+	d.EstimatedTokens = estimateTokens(d.Prompt)
+
+	return d
+}
+
+// text renders the dry run for people: the request details, the files and
+// the prompt itself
+func (d dryRun) text() string {
+	var b strings.Builder
+	b.WriteString("Dry run: nothing was sent.\n\n")
+	fmt.Fprintf(&b, "Provider:  %s\n", d.Provider)
+	fmt.Fprintf(&b, "Model:     %s\n", d.Model)
+	fmt.Fprintf(&b, "Profile:   %s\n", d.Profile)
+	fmt.Fprintf(&b, "Endpoint:  %s\n", d.Endpoint)
+	fmt.Fprintf(&b, "Prompt:    %d characters, about %d tokens\n", d.PromptChars, d.EstimatedTokens)
+	if d.Requests > 1 {
+		fmt.Fprintf(&b, "Requests:  %d, one per patch\n", d.Requests)
+	}
+
+	fmt.Fprintf(&b, "\nIncluded files (%d):\n", len(d.Included))
+	for _, file := range d.Included {
+		if n := d.Redacted[file]; n > 0 {
+			fmt.Fprintf(&b, "  %s (%d secrets redacted)\n", file, n)
+		} else {
+			fmt.Fprintf(&b, "  %s\n", file)
+		}
+	}
+	fmt.Fprintf(&b, "\nExcluded by ignore rules (%d):\n", len(d.Excluded))
+	for _, file := range d.Excluded {
+		fmt.Fprintf(&b, "  %s\n", file)
+	}
+
+	if d.Prompt == "" {
+		b.WriteString("\nNo changes detected: no request would be made.\n")
+		return b.String()
+	}
+
+	total := 0
+	files := make([]string, 0, len(d.Redacted))
+	for file, n := range d.Redacted {
+		total += n
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	if total > 0 {
+		fmt.Fprintf(&b, "\n%d secrets were replaced with %s in %s.\n", total, redactedMarker, strings.Join(files, ", "))
+	}
+
+	b.WriteString("\n----- prompt -----\n")
+	b.WriteString(d.Prompt)
+	if !strings.HasSuffix(d.Prompt, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("----- end of prompt -----\n")
+
+	return b.String()
+}
+
+// printDryRun prints the dry run as JSON with --format json and as text
+// otherwise
+func printDryRun(cli cliOptions, d dryRun) int {
+	if cli.format != "json" {
+		fmt.Print(d.text())
+		return exitOK
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		fmt.Println(theme.Error.Render("Error: " + err.Error()))
+		return exitError
+	}
+	fmt.Println(string(data))
+
+	return exitOK
+}
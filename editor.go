@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorMsg is sent when the editor opened for a finding exits
+type editorMsg struct {
+	err error
+}
+
+// editorCommand builds the command that opens file at line in the user's
+// editor from $VISUAL or $EDITOR
+func editorCommand(file string, line int) (*exec.Cmd, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return nil, fmt.Errorf("set $VISUAL or $EDITOR to open files from revyu")
+	}
+
+	parts := strings.Fields(editor)
+	args := parts[1:]
+	switch filepath.Base(parts[0]) {
+	case "code", "code-insiders", "cursor", "subl":
+		// These editors take file:line instead of +line
+		if line > 0 {
+			file = fmt.Sprintf("%s:%d", file, line)
+		}
+		if filepath.Base(parts[0]) != "subl" {
+			args = append(args, "-g")
+		}
+		args = append(args, file)
+	default:
+		if line > 0 {
+			args = append(args, fmt.Sprintf("+%d", line))
+		}
+		args = append(args, file)
+	}
+
+	return exec.Command(parts[0], args...), nil
+}
+
+// openInEditor suspends the TUI and opens the item's file at its first line
+func (m model) openInEditor(item ReviewItem) tea.Cmd {
+	if item.file == "" {
+		return func() tea.Msg {
+			return editorMsg{err: fmt.Errorf("finding #%d has no file reference", item.number)}
+		}
+	}
+
+	path := item.file
+	if root, err := gitToplevel(); err == nil && !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	cmd, err := editorCommand(path, item.startLine)
+	if err != nil {
+		return func() tea.Msg {
+			return editorMsg{err: err}
+		}
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorMsg{err: err}
+	})
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// reportFormats maps --output file extensions to report formats
+var reportFormats = map[string]string{
+	".md":       "markdown",
+	".markdown": "markdown",
+	".html":     "html",
+	".htm":      "html",
+}
+
+// reportFormat picks the export format from the file extension
+func reportFormat(path string) (string, error) {
+	format, ok := reportFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", fmt.Errorf("unsupported report file %q (use .md or .html)", path)
+	}
+
+	return format, nil
+}
+
+// reportEntry is a finding prepared for the exporters
+type reportEntry struct {
+	Number     int
+	Severity   string
+	Category   string
+	Location   string
+	Content    string
+	CodeBlocks []string
+	Hunk       string
+	Status     string
+}
+
+// reportData is everything the Markdown and HTML reports show
+type reportData struct {
+	Target   string
+	Base     string
+	Provider string
+	Model    string
+	Profile  string
+	Date     string
+	Duration string
+	Tokens   int
+	Summary  string
+	Resolved int
+	Total    int
+	Entries  []reportEntry
+}
+
+// newReportData collects the report contents from a finished run
+func newReportData(run reviewRun) reportData {
+	data := reportData{
+		Target:   run.target,
+		Provider: run.opts.Provider,
+		Model:    run.opts.Model,
+		Profile:  run.opts.Profile,
+		Tokens:   run.usage.TotalTokens,
+		Summary:  extractSection(run.review, "Summary"),
+	}
+	data.Base = run.base
+	if run.started.IsZero() {
+		data.Date = time.Now().Format("2006-01-02 15:04")
+	} else {
+		data.Date = run.started.Format("2006-01-02 15:04")
+	}
+	if run.duration > 0 {
+		data.Duration = run.duration.Round(100 * time.Millisecond).String()
+	}
+
+	for _, item := range run.items {
+		status := "open"
+		switch {
+		case item.dismissed:
+			status = "dismissed"
+		case item.checked:
+			status = "resolved"
+		}
+		if !item.dismissed {
+			data.Total++
+			if item.checked {
+				data.Resolved++
+			}
+		}
+
+		location := strings.TrimSpace(strings.ReplaceAll(item.title, "📄", ""))
+		if item.file != "" && item.startLine > 0 {
+			location = fmt.Sprintf("%s:%d", item.file, item.startLine)
+			if item.endLine > item.startLine {
+				location += fmt.Sprintf("-%d", item.endLine)
+			}
+		}
+
+		data.Entries = append(data.Entries, reportEntry{
+			Number:     item.number,
+			Severity:   string(item.severity),
+			Category:   string(item.category),
+			Location:   location,
+			Content:    item.content,
+			CodeBlocks: item.codeBlocks,
+			Hunk:       diffHunk(patchDiff(run.diff, run.patches, item), item.file, item.startLine),
+			Status:     status,
+		})
+	}
+
+	return data
+}
+
+// reportRun describes the TUI's final state as a run for the exporters
+func (m model) reportRun() reviewRun {
+	base, head := diffRevisions(m.filePath, m.staged, m.patches != nil)
+	return reviewRun{
+		target:  m.filePath,
+		base:    base,
+		head:    head,
+		diff:    m.diff,
+		patches: m.patches,
+		opts:    m.opts,
+		review:  m.review,
+		items:   m.items,
+	}
+}
+
+// writeReport exports the run to path as Markdown or HTML depending on the
+// file extension
+func writeReport(path string, run reviewRun) error {
+	format, err := reportFormat(path)
+	if err != nil {
+		return err
+	}
+
+	data := newReportData(run)
+	var out string
+	if format == "html" {
+		out, err = htmlReport(data)
+		if err != nil {
+			return fmt.Errorf("failed to render report: %v", err)
+		}
+	} else {
+		out = markdownReport(data)
+	}
+
+	if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// markdownCell makes text safe for a single Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
+
+// markdownReport renders the report as Markdown
+func markdownReport(data reportData) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Revyu review: %s\n\n", data.Target)
+
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Target | `%s` |\n", data.Target)
+	if data.Base != "" {
+		fmt.Fprintf(&b, "| Base | `%s` |\n", data.Base)
+	}
+	fmt.Fprintf(&b, "| Reviewer | %s / %s (%s profile) |\n", data.Provider, data.Model, data.Profile)
+	fmt.Fprintf(&b, "| Date | %s |\n", data.Date)
+	if data.Duration != "" {
+		fmt.Fprintf(&b, "| Duration | %s |\n", data.Duration)
+	}
+	if data.Tokens > 0 {
+		fmt.Fprintf(&b, "| Tokens | %d |\n", data.Tokens)
+	}
+	fmt.Fprintf(&b, "| Progress | %d of %d resolved |\n\n", data.Resolved, data.Total)
+
+	if data.Summary != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", data.Summary)
+	}
+
+	if len(data.Entries) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("## Findings\n\n")
+	b.WriteString("| # | Status | Severity | Category | Location | Finding |\n")
+	b.WriteString("|---|--------|----------|----------|----------|---------|\n")
+	for _, e := range data.Entries {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | `%s` | %s |\n", e.Number, e.Status, e.Severity, e.Category, e.Location, markdownCell(firstSentence(e.Content, 100)))
+	}
+
+	b.WriteString("\n## Details\n")
+	for _, e := range data.Entries {
+		box := " "
+		if e.Status == "resolved" {
+			box = "x"
+		}
+		fmt.Fprintf(&b, "\n### #%d %s %s: `%s`\n\n", e.Number, e.Severity, strings.ToLower(e.Category), e.Location)
+		fmt.Fprintf(&b, "- [%s] %s\n\n", box, e.Status)
+		if e.Content != "" {
+			b.WriteString(e.Content + "\n\n")
+		}
+		for _, code := range e.CodeBlocks {
+			fmt.Fprintf(&b, "```\n%s\n```\n\n", code)
+		}
+		if e.Hunk != "" {
+			fmt.Fprintf(&b, "<details><summary>Diff</summary>\n\n```diff\n%s\n```\n\n</details>\n", e.Hunk)
+		}
+	}
+
+	return b.String()
+}
+
+// codeKeywords are highlighted in code blocks. The review covers any
+// language, so this is the common subset rather than one grammar.
+var codeKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"def": true, "default": true, "defer": true, "do": true, "else": true, "elif": true,
+	"except": true, "false": true, "finally": true, "fn": true, "for": true, "func": true,
+	"function": true, "go": true, "if": true, "import": true, "in": true, "interface": true,
+	"let": true, "map": true, "match": true, "new": true, "nil": true, "None": true, "null": true,
+	"package": true, "pub": true, "range": true, "return": true, "select": true, "self": true,
+	"static": true, "struct": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "type": true, "var": true, "while": true, "with": true, "yield": true,
+}
+
+// highlightCode wraps comments, strings, numbers and keywords in spans. It is
+// a small tokenizer, not a parser, and errs on the side of leaving text plain.
+func highlightCode(code string) template.HTML {
+	var b strings.Builder
+	runes := []rune(code)
+	span := func(class string, text []rune) {
+		fmt.Fprintf(&b, `<span class="%s">%s</span>`, class, template.HTMLEscapeString(string(text)))
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/', r == '#':
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			span("c", runes[i:end])
+			i = end
+		case r == '"' || r == '\'' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r && runes[end] != '\n' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(runes) && runes[end] == r {
+				end++
+			}
+			if end > len(runes) {
+				end = len(runes)
+			}
+			span("s", runes[i:end])
+			i = end
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.' || runes[end] == 'x') {
+				end++
+			}
+			span("n", runes[i:end])
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			if codeKeywords[string(runes[i:end])] {
+				span("k", runes[i:end])
+			} else {
+				b.WriteString(template.HTMLEscapeString(string(runes[i:end])))
+			}
+			i = end
+		default:
+			b.WriteString(template.HTMLEscapeString(string(r)))
+			i++
+		}
+	}
+
+	return template.HTML(b.String())
+}
+
+// highlightDiff colours added and removed lines of a hunk
+func highlightDiff(hunk string) template.HTML {
+	var b strings.Builder
+	for _, line := range strings.Split(hunk, "\n") {
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			class = "h"
+		case strings.HasPrefix(line, "+"):
+			class = "a"
+		case strings.HasPrefix(line, "-"):
+			class = "d"
+		}
+		fmt.Fprintf(&b, `<span class="%s">%s</span>`+"\n", class, template.HTMLEscapeString(line))
+	}
+
+	return template.HTML(b.String())
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"code":  highlightCode,
+	"diff":  highlightDiff,
+	"lower": strings.ToLower,
+	"short": func(text string) string { return firstSentence(text, 100) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Revyu review: {{.Target}}</title>
+<style>
+body { font: 15px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 960px; margin: 2em auto; padding: 0 1em; }
+h1 { color: #7d56f4; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code, pre { font: 13px/1.45 ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+pre { background: #f6f8fa; padding: 12px; overflow-x: auto; border-radius: 6px; }
+.badge { display: inline-block; padding: 0 6px; border-radius: 4px; color: #fff; font-weight: bold; font-size: 12px; }
+.High { background: #cf222e; } .Medium { background: #bf8700; } .Low { background: #1a7f37; }
+.resolved { color: #1a7f37; } .open { color: #cf222e; } .dismissed { color: #6e7781; text-decoration: line-through; }
+.finding { border-top: 1px solid #d0d7de; padding-top: 0.5em; }
+.k { color: #cf222e; } .s { color: #0a3069; } .c { color: #6e7781; font-style: italic; } .n { color: #0550ae; }
+.a { background: #dafbe1; display: block; } .d { background: #ffebe9; display: block; } .h { color: #8250df; display: block; }
+</style>
+</head>
+<body>
+<h1>Revyu review: {{.Target}}</h1>
+<table>
+<tr><th>Target</th><td><code>{{.Target}}</code></td></tr>
+{{if .Base}}<tr><th>Base</th><td><code>{{.Base}}</code></td></tr>{{end}}
+<tr><th>Reviewer</th><td>{{.Provider}} / {{.Model}} ({{.Profile}} profile)</td></tr>
+<tr><th>Date</th><td>{{.Date}}</td></tr>
+{{if .Duration}}<tr><th>Duration</th><td>{{.Duration}}</td></tr>{{end}}
+{{if .Tokens}}<tr><th>Tokens</th><td>{{.Tokens}}</td></tr>{{end}}
+<tr><th>Progress</th><td>{{.Resolved}} of {{.Total}} resolved</td></tr>
+</table>
+{{if .Summary}}<h2>Summary</h2>
+<p>{{.Summary}}</p>{{end}}
+{{if .Entries}}<h2>Findings</h2>
+<table>
+<tr><th>#</th><th>Status</th><th>Severity</th><th>Category</th><th>Location</th><th>Finding</th></tr>
+{{range .Entries}}<tr><td><a href="#f{{.Number}}">{{.Number}}</a></td><td class="{{.Status}}">{{.Status}}</td><td><span class="badge {{.Severity}}">{{.Severity}}</span></td><td>{{.Category}}</td><td><code>{{.Location}}</code></td><td>{{short .Content}}</td></tr>
+{{end}}</table>
+<h2>Details</h2>
+{{range .Entries}}<div class="finding" id="f{{.Number}}">
+<h3>#{{.Number}} <span class="badge {{.Severity}}">{{.Severity}}</span> {{lower .Category}}: <code>{{.Location}}</code></h3>
+<p class="{{.Status}}">{{if eq .Status "resolved"}}&#9745;{{else}}&#9744;{{end}} {{.Status}}</p>
+{{if .Content}}<p>{{.Content}}</p>{{end}}
+{{range .CodeBlocks}}<pre>{{code .}}</pre>
+{{end}}{{if .Hunk}}<details><summary>Diff</summary><pre>{{diff .Hunk}}</pre></details>{{end}}
+</div>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// htmlReport renders the report as a single HTML file with embedded CSS
+func htmlReport(data reportData) (string, error) {
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// active reports whether any search query or filter toggle is set
+func (f filterState) active() bool {
+	return f.query != "" || f.severity != "" || f.checked != checkedAll || f.file != "" || f.category != ""
+}
+
+// matches reports whether an item passes the search query and every filter
+func (f filterState) matches(item ReviewItem) bool {
+	if f.severity != "" && item.severity != f.severity {
+		return false
+	}
+
+	switch f.checked {
+	case checkedOnly:
+		if !item.checked {
+			return false
+		}
+	case uncheckedOnly:
+		if item.checked {
+			return false
+		}
+	}
+
+	if f.file != "" && item.file != f.file {
+		return false
+	}
+
+	if f.category != "" && item.category != f.category {
+		return false
+	}
+
+	if f.query != "" {
+		query := strings.ToLower(f.query)
+		haystack := strings.ToLower(item.title + "\n" + item.content + "\n" + strings.Join(item.codeBlocks, "\n"))
+		if !strings.Contains(haystack, query) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describe renders the active filters for the subtitle line
+func (f filterState) describe() string {
+	var parts []string
+	if f.query != "" {
+		parts = append(parts, fmt.Sprintf("/%s", f.query))
+	}
+	if f.severity != "" {
+		parts = append(parts, "severity="+string(f.severity))
+	}
+	switch f.checked {
+	case checkedOnly:
+		parts = append(parts, "checked")
+	case uncheckedOnly:
+		parts = append(parts, "unchecked")
+	}
+	if f.file != "" {
+		parts = append(parts, "file="+f.file)
+	}
+	if f.category != "" {
+		parts = append(parts, "category="+string(f.category))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// visibleItems returns the indices of items that pass the current filter,
+// leaving out dismissed findings
+func (m model) visibleItems() []int {
+	visible := []int{}
+	for i, item := range m.items {
+		if !item.dismissed && m.filter.matches(item) {
+			visible = append(visible, i)
+		}
+	}
+
+	return visible
+}
+
+// clampCursor keeps the cursor inside the checklist rows after a filter,
+// grouping or collapse change
+func (m *model) clampCursor() {
+	count := len(m.rows())
+	if m.cursorPos >= count {
+		m.cursorPos = count - 1
+	}
+	if m.cursorPos < 0 {
+		m.cursorPos = 0
+	}
+}
+
+func nextSeverityFilter(current Severity) Severity {
+	switch current {
+	case "":
+		return SeverityHigh
+	case SeverityHigh:
+		return SeverityMedium
+	case SeverityMedium:
+		return SeverityLow
+	default:
+		return ""
+	}
+}
+
+func nextCheckedFilter(current checkedFilter) checkedFilter {
+	switch current {
+	case checkedAll:
+		return uncheckedOnly
+	case uncheckedOnly:
+		return checkedOnly
+	default:
+		return checkedAll
+	}
+}
+
+func nextCategoryFilter(current Category) Category {
+	switch current {
+	case "":
+		return CategoryIssue
+	case CategoryIssue:
+		return CategorySuggestion
+	default:
+		return ""
+	}
+}
+
+// nextFileFilter cycles through the distinct files referenced by the items,
+// returning to "all files" after the last one
+func nextFileFilter(current string, items []ReviewItem) string {
+	seen := map[string]bool{}
+	files := []string{}
+	for _, item := range items {
+		if item.file != "" && !seen[item.file] {
+			seen[item.file] = true
+			files = append(files, item.file)
+		}
+	}
+	sort.Strings(files)
+
+	if current == "" {
+		if len(files) == 0 {
+			return ""
+		}
+		return files[0]
+	}
+
+	for i, file := range files {
+		if file == current && i+1 < len(files) {
+			return files[i+1]
+		}
+	}
+
+	return ""
+}
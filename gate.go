@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseSeverity reads a --fail-on value such as "high"
+func parseSeverity(value string) (Severity, error) {
+	for _, severity := range []Severity{SeverityHigh, SeverityMedium, SeverityLow} {
+		if strings.EqualFold(value, string(severity)) {
+			return severity, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown severity %q (use high, medium or low)", value)
+}
+
+// gateFailures checks a finished run against --fail-on and --max-findings and
+// returns a reason for every limit that was exceeded. Findings dismissed in
+// earlier runs are not counted.
+func gateFailures(cli cliOptions, items []ReviewItem) []string {
+	total, atOrAbove := 0, 0
+	for _, item := range items {
+		if item.dismissed {
+			continue
+		}
+		total++
+		if cli.failOn != "" && severityRank(item.severity) <= severityRank(cli.failOn) {
+			atOrAbove++
+		}
+	}
+
+	var reasons []string
+	if cli.failOn != "" && atOrAbove > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d findings at or above %s severity", atOrAbove, cli.failOn))
+	}
+	if cli.maxFindings >= 0 && total > cli.maxFindings {
+		reasons = append(reasons, fmt.Sprintf("%d findings exceed the maximum of %d", total, cli.maxFindings))
+	}
+
+	return reasons
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Severity
+		wantErr bool
+	}{
+		{"high", SeverityHigh, false},
+		{"Medium", SeverityMedium, false},
+		{"LOW", SeverityLow, false},
+		{"critical", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSeverity(tt.value)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseSeverity(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGateFailures(t *testing.T) {
+	items := []ReviewItem{
+		{severity: SeverityHigh},
+		{severity: SeverityMedium},
+		{severity: SeverityLow},
+		{severity: SeverityHigh, dismissed: true},
+	}
+
+	tests := []struct {
+		name  string
+		cli   cliOptions
+		items []ReviewItem
+		want  []string
+	}{
+		{"no gates", cliOptions{maxFindings: -1}, items, nil},
+		{"fail on high", cliOptions{failOn: SeverityHigh, maxFindings: -1}, items, []string{"1 findings at or above High severity"}},
+		{"fail on medium", cliOptions{failOn: SeverityMedium, maxFindings: -1}, items, []string{"2 findings at or above Medium severity"}},
+		{"fail on low", cliOptions{failOn: SeverityLow, maxFindings: -1}, items, []string{"3 findings at or above Low severity"}},
+		{"only dismissed above the threshold", cliOptions{failOn: SeverityHigh, maxFindings: -1}, items[1:], nil},
+		{"under the maximum", cliOptions{maxFindings: 3}, items, nil},
+		{"over the maximum", cliOptions{maxFindings: 2}, items, []string{"3 findings exceed the maximum of 2"}},
+		{"zero findings allowed", cliOptions{maxFindings: 0}, items, []string{"3 findings exceed the maximum of 0"}},
+		{"both gates", cliOptions{failOn: SeverityHigh, maxFindings: 1}, items, []string{
+			"1 findings at or above High severity",
+			"3 findings exceed the maximum of 1",
+		}},
+		{"no findings", cliOptions{failOn: SeverityLow, maxFindings: 0}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gateFailures(tt.cli, tt.items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("gateFailures = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
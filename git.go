@@ -3,24 +3,108 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"strings"
+	"time"
 )
 
-// getGitDiff executes git diff command and returns the output
-func getGitDiff(filePath string) (string, error) {
-	var cmd *exec.Cmd
+// runGit runs git with the given arguments and returns its combined output.
+// Every call is recorded in the debug log with its duration.
+func runGit(args ...string) ([]byte, error) {
+	start := time.Now()
+	output, err := exec.Command("git", args...).CombinedOutput()
 
-	if filePath == "." {
-		// Get diff for all files
-		cmd = exec.Command("git", "diff")
-	} else {
-		// Get diff for specific file
-		cmd = exec.Command("git", "diff", filePath)
+	attrs := []any{"command", "git " + strings.Join(args, " "), "bytes", len(output), "duration", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	debugLog.Debug("git", attrs...)
+
+	return output, err
+}
+
+// getGitDiff executes git diff command and returns the output. With staged
+// set it diffs the index instead of the working tree.
+func getGitDiff(filePath string, staged bool) (string, error) {
+	args := []string{"diff"}
+	if staged {
+		args = append(args, "--cached")
 	}
 
-	output, err := cmd.CombinedOutput()
+	if filePath != "." {
+		// Get diff for specific file
+		args = append(args, filePath)
+	}
+	output, err := runGit(args...)
 	if err != nil {
 		return "", fmt.Errorf("git diff failed: %v\nOutput: %s", err, string(output))
 	}
 
 	return string(output), nil
 }
+
+// gitDir returns the absolute path of the repository's .git directory
+func gitDir() (string, error) {
+	output, err := runGit("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %v\nOutput: %s", err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitToplevel returns the absolute path of the repository's working tree root
+func gitToplevel() (string, error) {
+	output, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %v\nOutput: %s", err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitRevParse resolves a revision such as HEAD to its commit hash
+func gitRevParse(rev string) (string, error) {
+	output, err := runGit("rev-parse", "--verify", "--quiet", rev)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse %s failed: %v\nOutput: %s", rev, err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitRemoteURL returns the fetch URL of a remote such as origin
+func gitRemoteURL(remote string) (string, error) {
+	output, err := runGit("remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("git remote get-url %s failed: %v\nOutput: %s", remote, err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// remoteProjectPath extracts the "owner/name" project path from a remote URL
+// in either the scp-like SSH form or the URL form
+func remoteProjectPath(url string) string {
+	path := url
+	if idx := strings.Index(path, "://"); idx >= 0 {
+		path = path[idx+3:]
+		if slash := strings.Index(path, "/"); slash >= 0 {
+			path = path[slash+1:]
+		}
+	} else if colon := strings.Index(path, ":"); colon >= 0 {
+		path = path[colon+1:]
+	}
+
+	return strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+}
+
+// gitHooksDir returns the directory git runs hooks from, honouring
+// core.hooksPath
+func gitHooksDir() (string, error) {
+	output, err := runGit("rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %v\nOutput: %s", err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
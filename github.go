@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// annotationCommand maps severity to the GitHub Actions workflow command
+func annotationCommand(severity Severity) string {
+	switch severity {
+	case SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeData escapes the message of a workflow command
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a key=value property of a workflow command
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// githubAnnotations renders the run as workflow commands, one per finding
+// that was not dismissed, so they show up on the pull request's diff
+func githubAnnotations(run reviewRun) string {
+	var b strings.Builder
+	for _, item := range run.items {
+		if item.dismissed {
+			continue
+		}
+
+		title := fmt.Sprintf("revyu: %s %s #%d", item.severity, strings.ToLower(string(item.category)), item.number)
+		props := []string{}
+		if item.file != "" {
+			props = append(props, "file="+escapeProperty(item.file))
+			if item.startLine > 0 {
+				props = append(props, fmt.Sprintf("line=%d", item.startLine))
+			}
+			if item.endLine > item.startLine {
+				props = append(props, fmt.Sprintf("endLine=%d", item.endLine))
+			}
+		}
+		props = append(props, "title="+escapeProperty(title))
+
+		message := item.content
+		if message == "" {
+			message = strings.TrimSpace(strings.ReplaceAll(item.title, "📄", ""))
+		}
+		fmt.Fprintf(&b, "::%s %s::%s\n", annotationCommand(item.severity), strings.Join(props, ","), escapeData(message))
+	}
+
+	return b.String()
+}
+
+// stepSummary renders the Markdown job summary for the Actions run page
+func stepSummary(run reviewRun) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Revyu review: %s\n\n", run.target)
+
+	if summary := extractSection(run.review, "Summary"); summary != "" {
+		b.WriteString(summary + "\n\n")
+	}
+
+	counts := map[Severity]int{}
+	open := 0
+	for _, item := range run.items {
+		if !item.dismissed {
+			counts[item.severity]++
+			open++
+		}
+	}
+	fmt.Fprintf(&b, "**%d findings**: %d High, %d Medium, %d Low  \n", open, counts[SeverityHigh], counts[SeverityMedium], counts[SeverityLow])
+	fmt.Fprintf(&b, "Reviewed with `%s` (%s profile) in %.1fs, %d tokens\n\n", run.opts.Model, run.opts.Profile, run.duration.Seconds(), run.usage.TotalTokens)
+
+	if open == 0 {
+		return b.String()
+	}
+
+	b.WriteString("| # | Severity | Category | Location | Finding |\n")
+	b.WriteString("|---|----------|----------|----------|---------|\n")
+	for _, item := range run.items {
+		if item.dismissed {
+			continue
+		}
+		location := item.file
+		if item.startLine > 0 {
+			location = fmt.Sprintf("%s:%d", item.file, item.startLine)
+		}
+		finding := strings.ReplaceAll(strings.ReplaceAll(item.content, "|", "\\|"), "\n", " ")
+		fmt.Fprintf(&b, "| %d | %s | %s | `%s` | %s |\n", item.number, item.severity, item.category, location, finding)
+	}
+
+	return b.String()
+}
+
+// writeStepSummary appends the job summary to $GITHUB_STEP_SUMMARY. Outside
+// of GitHub Actions the variable is unset and nothing is written.
+func writeStepSummary(run reviewRun) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(stepSummary(run)); err != nil {
+		return fmt.Errorf("failed to write job summary: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+func TestEscapeProperty(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"main.go", "main.go"},
+		{"dir/a,b.go", "dir/a%2Cb.go"},
+		{"revyu: High issue #1", "revyu%3A High issue #1"},
+		{"100%", "100%25"},
+		{"two\r\nlines", "two%0D%0Alines"},
+		{"%3A", "%253A"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeProperty(tt.in); got != tt.want {
+			t.Errorf("escapeProperty(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeData(t *testing.T) {
+	if got, want := escapeData("a: b, 50%\nnext"), "a: b, 50%25%0Anext"; got != want {
+		t.Errorf("escapeData = %q, want %q", got, want)
+	}
+}
+
+func TestGithubAnnotations(t *testing.T) {
+	tests := []struct {
+		name string
+		item ReviewItem
+		want string
+	}{
+		{
+			name: "high issue on a range",
+			item: ReviewItem{number: 1, severity: SeverityHigh, category: CategoryIssue, file: "a.go", startLine: 3, endLine: 5, content: "Leak"},
+			want: "::error file=a.go,line=3,endLine=5,title=revyu%3A High issue #1::Leak\n",
+		},
+		{
+			name: "medium on one line",
+			item: ReviewItem{number: 2, severity: SeverityMedium, category: CategorySuggestion, file: "b,c.go", startLine: 7, endLine: 7, content: "Rename"},
+			want: "::warning file=b%2Cc.go,line=7,title=revyu%3A Medium suggestion #2::Rename\n",
+		},
+		{
+			name: "low without a location falls back to the title",
+			item: ReviewItem{number: 3, severity: SeverityLow, category: CategorySuggestion, title: "3. **Docs** 📄 README"},
+			want: "::notice title=revyu%3A Low suggestion #3::3. **Docs**  README\n",
+		},
+		{
+			name: "multi-line message",
+			item: ReviewItem{number: 4, severity: SeverityLow, category: CategoryIssue, file: "a.go", content: "first\nsecond"},
+			want: "::notice file=a.go,title=revyu%3A Low issue #4::first%0Asecond\n",
+		},
+		{
+			name: "dismissed",
+			item: ReviewItem{number: 5, severity: SeverityHigh, category: CategoryIssue, content: "Hidden", dismissed: true},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := githubAnnotations(reviewRun{items: []ReviewItem{tt.item}}); got != tt.want {
+				t.Errorf("githubAnnotations =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultGitLabURL is used unless --url, $CI_SERVER_URL or the config point
+// at a self-hosted instance
+const defaultGitLabURL = "https://gitlab.com"
+
+// gitlabClient talks to the GitLab REST API for one project
+type gitlabClient struct {
+	baseURL string
+	token   string
+	project string
+	http    *http.Client
+}
+
+// glVersion is one diff version of a merge request. Discussions on the diff
+// are anchored with its three SHAs.
+type glVersion struct {
+	ID             int64  `json:"id"`
+	BaseCommitSHA  string `json:"base_commit_sha"`
+	HeadCommitSHA  string `json:"head_commit_sha"`
+	StartCommitSHA string `json:"start_commit_sha"`
+	Diffs          []struct {
+		OldPath     string `json:"old_path"`
+		NewPath     string `json:"new_path"`
+		Diff        string `json:"diff"`
+		NewFile     bool   `json:"new_file"`
+		DeletedFile bool   `json:"deleted_file"`
+	} `json:"diffs"`
+}
+
+type glNote struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+type glDiscussion struct {
+	ID    string   `json:"id"`
+	Notes []glNote `json:"notes"`
+}
+
+type glPosition struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha"`
+	HeadSHA      string `json:"head_sha"`
+	StartSHA     string `json:"start_sha"`
+	OldPath      string `json:"old_path"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+	OldLine      int    `json:"old_line,omitempty"`
+}
+
+// request sends one API request and returns the response body. Non-2xx
+// responses are turned into errors that include GitLab's message.
+func (c gitlabClient) request(method, path string, body any) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(c.baseURL, "/")+"/api/v4"+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitLab API call failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message any    `json:"message"`
+			Error   string `json:"error"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		message := apiErr.Error
+		if apiErr.Message != nil {
+			message = fmt.Sprint(apiErr.Message)
+		}
+		return nil, fmt.Errorf("GitLab API %s %s: %s: %s", method, path, resp.Status, message)
+	}
+
+	return data, nil
+}
+
+// getJSON fetches a path and decodes the JSON response into out
+func (c gitlabClient) getJSON(path string, out any) error {
+	data, err := c.request("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	return nil
+}
+
+// unifiedDiff rebuilds a git-style diff from the per-file diffs of a version
+func (v glVersion) unifiedDiff() string {
+	var b strings.Builder
+	for _, d := range v.Diffs {
+		oldPath, newPath := "a/"+d.OldPath, "b/"+d.NewPath
+		if d.NewFile {
+			oldPath = "/dev/null"
+		}
+		if d.DeletedFile {
+			newPath = "/dev/null"
+		}
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- %s\n+++ %s\n", d.OldPath, d.NewPath, oldPath, newPath)
+		b.WriteString(d.Diff)
+		if !strings.HasSuffix(d.Diff, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// gitlabOptions holds the flags of `revyu gitlab review`
+type gitlabOptions struct {
+	mr      int
+	project string
+	url     string
+
+	// settings holds the config values given as flags, keyed by setting
+	settings map[string]string
+}
+
+// gitlabFlags defines the flags of `revyu gitlab review`
+func gitlabFlags(opts *gitlabOptions) *flag.FlagSet {
+	fs := newFlagSet("gitlab review")
+	fs.IntVar(&opts.mr, "mr", 0, "merge request `IID`")
+	fs.StringVar(&opts.project, "project", "", "project path such as `group/project`")
+	fs.StringVar(&opts.url, "url", "", "GitLab instance `URL`")
+	debugFlags(fs)
+	return fs
+}
+
+func parseGitLabArgs(args []string) (gitlabOptions, error) {
+	var opts gitlabOptions
+
+	fs := gitlabFlags(&opts)
+	if err := parseFlags(fs, args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.mr <= 0 {
+		return opts, fmt.Errorf("--mr is required")
+	}
+
+	if opts.project == "" {
+		opts.project = os.Getenv("CI_PROJECT_PATH")
+	}
+	if opts.project == "" {
+		if remote, err := gitRemoteURL("origin"); err == nil {
+			opts.project = remoteProjectPath(remote)
+		}
+	}
+	if !strings.Contains(opts.project, "/") {
+		return opts, fmt.Errorf("could not determine the project, pass --project group/project")
+	}
+	opts.settings = flagSettings(fs)
+
+	return opts, nil
+}
+
+// printGitLabUsage prints the help of `revyu gitlab review`
+func printGitLabUsage() {
+	printHelp("gitlab review --mr N [--project group/project] [--url URL]",
+		"Reviews a merge request and posts the findings as diff discussions.\n"+
+			"The project or personal access token is read from $GITLAB_TOKEN.",
+		gitlabFlags(&gitlabOptions{}))
+}
+
+// runGitLab runs `revyu gitlab <command>` and returns the exit code
+func runGitLab(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		printGitLabUsage()
+		return exitOK
+	}
+	if len(args) == 0 || args[0] != "review" {
+		printGitLabUsage()
+		return exitUsage
+	}
+
+	opts, err := parseGitLabArgs(args[1:])
+	if err != nil {
+		return usageError(err, printGitLabUsage)
+	}
+
+	cfg, _, _, ok := loadSettings(opts.settings)
+	if !ok {
+		return exitError
+	}
+	for _, u := range []string{opts.url, os.Getenv("CI_SERVER_URL"), cfg.GitLabURL, defaultGitLabURL} {
+		if u != "" {
+			opts.url = u
+			break
+		}
+	}
+
+	closeLog, err := startLogging(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return exitError
+	}
+	defer closeLog()
+
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "Error: set GITLAB_TOKEN to a project or personal access token with the api scope")
+		return exitError
+	}
+	addLogSecret(token)
+
+	apiKey, ok := requireAPIKey(cfg)
+	if !ok {
+		return exitError
+	}
+
+	client := gitlabClient{
+		baseURL: opts.url,
+		token:   token,
+		project: opts.project,
+		http:    &http.Client{Timeout: 60 * time.Second},
+	}
+
+	summary, err := reviewMergeRequest(client, apiKey, opts.mr, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error reviewing merge request: "+err.Error())
+		return exitError
+	}
+
+	fmt.Println(summary)
+	return exitOK
+}
+
+// reviewMergeRequest reviews the latest diff version of a merge request and
+// posts one discussion per finding plus a summary note. Notes from earlier
+// runs are matched by fingerprint and edited in place.
+func reviewMergeRequest(c gitlabClient, apiKey string, iid int, cfg Config) (string, error) {
+	mrPath := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(c.project), iid)
+
+	versions, err := listAll[glVersion](c, mrPath+"/versions")
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("merge request !%d has no diff versions", iid)
+	}
+
+	// Versions are listed newest first
+	var version glVersion
+	if err := c.getJSON(fmt.Sprintf("%s/versions/%d", mrPath, versions[0].ID), &version); err != nil {
+		return "", err
+	}
+	diff := prepareDiff(version.unifiedDiff(), cfg.Ignore, cfg.Redact).diff
+	if strings.TrimSpace(diff) == "" {
+		return "Merge request has no changes", nil
+	}
+
+	run, err := runReview(apiKey, fmt.Sprintf("%s!%d", c.project, iid), diff, cfg.reviewOptions())
+	if err != nil {
+		return "", err
+	}
+
+	discussions, err := listAll[glDiscussion](c, mrPath+"/discussions")
+	if err != nil {
+		return "", err
+	}
+	posted := map[string]string{}
+	var summaryNote int64
+	for _, discussion := range discussions {
+		for _, note := range discussion.Notes {
+			if match := fingerprintPattern.FindStringSubmatch(note.Body); match != nil {
+				posted[match[1]] = fmt.Sprintf("%s/discussions/%s/notes/%d", mrPath, discussion.ID, note.ID)
+			}
+			if strings.Contains(note.Body, summaryMarker) {
+				summaryNote = note.ID
+			}
+		}
+	}
+
+	files := parseDiffLines(diff)
+	var outside []ReviewItem
+	created, updated := 0, 0
+	for _, item := range run.items {
+		if item.dismissed {
+			continue
+		}
+
+		_, end, ok := commentRange(files, item)
+		body := findingBody(item)
+
+		if notePath, found := posted[itemFingerprint(item)]; found {
+			if _, err := c.request("PUT", notePath, map[string]string{"body": body}); err != nil {
+				return "", err
+			}
+			updated++
+			continue
+		}
+		if !ok {
+			outside = append(outside, item)
+			continue
+		}
+
+		file := files[item.file]
+		position := glPosition{
+			PositionType: "text",
+			BaseSHA:      version.BaseCommitSHA,
+			HeadSHA:      version.HeadCommitSHA,
+			StartSHA:     version.StartCommitSHA,
+			OldPath:      file.oldPath,
+			NewPath:      item.file,
+			NewLine:      end,
+			OldLine:      file.lines[end].oldLine,
+		}
+		discussion := map[string]any{"body": body, "position": position}
+		if _, err := c.request("POST", mrPath+"/discussions", discussion); err != nil {
+			return "", err
+		}
+		created++
+	}
+
+	summary := map[string]string{"body": summaryBody(run, outside)}
+	if summaryNote != 0 {
+		_, err = c.request("PUT", fmt.Sprintf("%s/notes/%d", mrPath, summaryNote), summary)
+	} else {
+		_, err = c.request("POST", mrPath+"/notes", summary)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Posted %d new discussions and updated %d on %s!%d", created, updated, c.project, iid), nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	noFileGroup  = "(no file)"
+	noPatchGroup = "(no patch)"
+)
+
+func (g groupMode) String() string {
+	switch g {
+	case groupByFile:
+		return "file"
+	case groupBySeverity:
+		return "severity"
+	case groupByCategory:
+		return "category"
+	case groupByPatch:
+		return "patch"
+	default:
+		return "none"
+	}
+}
+
+func (o sortOrder) String() string {
+	switch o {
+	case sortSeverityDesc:
+		return "severity"
+	case sortFileLine:
+		return "file/line"
+	default:
+		return "review order"
+	}
+}
+
+// nextGroupMode cycles the grouping. Grouping by patch is only offered for
+// the review of a patch series.
+func nextGroupMode(current groupMode, series bool) groupMode {
+	next := (current + 1) % (groupByPatch + 1)
+	if next == groupByPatch && !series {
+		return groupNone
+	}
+
+	return next
+}
+
+func nextSortOrder(current sortOrder) sortOrder {
+	return (current + 1) % (sortFileLine + 1)
+}
+
+// severityRank orders severities from most to least important
+func severityRank(severity Severity) int {
+	switch severity {
+	case SeverityHigh:
+		return 0
+	case SeverityMedium:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// groupKey returns the name of the group an item belongs to
+func groupKey(item ReviewItem, mode groupMode) string {
+	switch mode {
+	case groupByFile:
+		if item.file == "" {
+			return noFileGroup
+		}
+		return item.file
+	case groupBySeverity:
+		return string(item.severity)
+	case groupByCategory:
+		return string(item.category)
+	case groupByPatch:
+		if item.patch == "" {
+			return noPatchGroup
+		}
+		return item.patch
+	default:
+		return ""
+	}
+}
+
+// sortItems orders item indices in place without disturbing ties
+func sortItems(indices []int, items []ReviewItem, order sortOrder) {
+	switch order {
+	case sortSeverityDesc:
+		sort.SliceStable(indices, func(a, b int) bool {
+			return severityRank(items[indices[a]].severity) < severityRank(items[indices[b]].severity)
+		})
+	case sortFileLine:
+		sort.SliceStable(indices, func(a, b int) bool {
+			left, right := items[indices[a]], items[indices[b]]
+			if left.file != right.file {
+				return left.file < right.file
+			}
+			return left.startLine < right.startLine
+		})
+	}
+}
+
+// groupLess orders group headers so severities read High to Low and files alphabetically
+func groupLess(left, right string, mode groupMode) bool {
+	switch mode {
+	case groupBySeverity:
+		return severityRank(Severity(left)) < severityRank(Severity(right))
+	case groupByCategory:
+		return left == string(CategoryIssue) && right != string(CategoryIssue)
+	case groupByPatch:
+		// Patches keep their order in the series
+		var l, r int
+		fmt.Sscanf(left, "%d/", &l)
+		fmt.Sscanf(right, "%d/", &r)
+		return l < r
+	default:
+		if left == noFileGroup || right == noFileGroup {
+			return right == noFileGroup && left != noFileGroup
+		}
+		return left < right
+	}
+}
+
+// rows builds the selectable checklist lines from the visible items, applying
+// the sort order, grouping and collapsed groups
+func (m model) rows() []listRow {
+	visible := m.visibleItems()
+	sortItems(visible, m.items, m.sorting)
+
+	rows := []listRow{}
+	if m.grouping == groupNone {
+		for _, idx := range visible {
+			rows = append(rows, listRow{item: idx})
+		}
+		return rows
+	}
+
+	groups := map[string][]int{}
+	names := []string{}
+	for _, idx := range visible {
+		key := groupKey(m.items[idx], m.grouping)
+		if _, ok := groups[key]; !ok {
+			names = append(names, key)
+		}
+		groups[key] = append(groups[key], idx)
+	}
+	sort.SliceStable(names, func(a, b int) bool {
+		return groupLess(names[a], names[b], m.grouping)
+	})
+
+	for _, name := range names {
+		members := groups[name]
+		checked := 0
+		for _, idx := range members {
+			if m.items[idx].checked {
+				checked++
+			}
+		}
+		rows = append(rows, listRow{group: name, item: -1, count: len(members), checked: checked})
+		if m.collapsed[name] {
+			continue
+		}
+		for _, idx := range members {
+			rows = append(rows, listRow{group: name, item: idx})
+		}
+	}
+
+	return rows
+}
+
+// jumpGroup moves the cursor to the next (dir > 0) or previous (dir < 0) group header
+func (m *model) jumpGroup(rows []listRow, dir int) {
+	for i := m.cursorPos + dir; i >= 0 && i < len(rows); i += dir {
+		if rows[i].item == -1 && rows[i].group != rows[m.cursorPos].group {
+			m.cursorPos = i
+			return
+		}
+	}
+}
+
+// toggleCollapse folds or unfolds the group under the cursor and moves the
+// cursor onto its header so it stays visible
+func (m *model) toggleCollapse(rows []listRow) {
+	if m.grouping == groupNone || m.cursorPos >= len(rows) {
+		return
+	}
+
+	group := rows[m.cursorPos].group
+	if m.collapsed == nil {
+		m.collapsed = map[string]bool{}
+	}
+	m.collapsed[group] = !m.collapsed[group]
+
+	for i, row := range m.rows() {
+		if row.item == -1 && row.group == group {
+			m.cursorPos = i
+			return
+		}
+	}
+}
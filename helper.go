@@ -1,5 +1,16 @@
 package main
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
 func checkEmpty(str string) bool {
 	return str == ""
 }
+
+// diffHash returns a stable hex identifier for a diff
+func diffHash(diff string) string {
+	sum := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// savedSessions reads every saved session of this repository, newest first
+func savedSessions() ([]SessionState, error) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	states := []SessionState{}
+	for _, path := range matches {
+		if state, err := readSession(path); err == nil {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(a, b int) bool {
+		return states[a].SavedAt.After(states[b].SavedAt)
+	})
+
+	return states, nil
+}
+
+// runHistory runs `revyu history` and returns the exit code
+func runHistory(args []string) int {
+	fs := newFlagSet("history")
+	limit := fs.Int("limit", 20, "show at most `N` sessions")
+	usage := func() {
+		printHelp("history [--limit N]", "Lists the review sessions saved for this repository, newest first.", fs)
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(err, usage)
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q", fs.Arg(0)), usage)
+	}
+	if *limit <= 0 {
+		return usageError(fmt.Errorf("--limit must be positive"), usage)
+	}
+
+	states, err := savedSessions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+	if len(states) == 0 {
+		fmt.Println(theme.Subtitle.Render("No saved sessions"))
+		return exitOK
+	}
+
+	for i, state := range states {
+		if i == *limit {
+			fmt.Println(theme.Content.Render(theme.plain(fmt.Sprintf("… %d older sessions", len(states)-i))))
+			break
+		}
+		fmt.Println(theme.Content.Render(fmt.Sprintf("%s  %-24s %d/%d checked  diff %s  %s/%s",
+			state.SavedAt.Local().Format("2006-01-02 15:04"),
+			state.Target,
+			state.checkedCount(),
+			len(state.Items),
+			state.DiffHash[:min(12, len(state.DiffHash))],
+			state.Model,
+			state.Profile)))
+	}
+
+	return exitOK
+}
+
+// cacheDir returns the directory revyu keeps sessions and transcripts in
+func cacheDir() (string, error) {
+	dir, err := gitDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "revyu"), nil
+}
+
+// runCache runs `revyu cache info|clear` and returns the exit code
+func runCache(args []string) int {
+	fs := newFlagSet("cache")
+	usage := func() {
+		printHelp("cache info|clear",
+			"Shows or removes the saved sessions and chat transcripts revyu keeps in\n"+
+				"the repository's .git directory.",
+			fs)
+	}
+
+	if len(args) > 0 && isHelp(args[0]) {
+		usage()
+		return exitOK
+	}
+	if len(args) == 0 || (args[0] != "info" && args[0] != "clear") {
+		usage()
+		return exitUsage
+	}
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return usageError(err, usage)
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q", fs.Arg(0)), usage)
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+
+	if args[0] == "clear" {
+		if err := os.RemoveAll(dir); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("failed to remove %s: %v", dir, err)))
+			return exitError
+		}
+		fmt.Println(theme.Success.Render("Removed " + dir))
+		return exitOK
+	}
+
+	sessions, transcripts := 0, 0
+	var size int64
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		size += info.Size()
+		switch {
+		case strings.HasSuffix(path, ".json"):
+			sessions++
+		case strings.HasPrefix(info.Name(), "transcript-"):
+			transcripts++
+		}
+		return nil
+	})
+
+	fmt.Println(theme.Content.Render("Directory:   " + dir))
+	fmt.Println(theme.Content.Render(fmt.Sprintf("Sessions:    %d", sessions)))
+	fmt.Println(theme.Content.Render(fmt.Sprintf("Transcripts: %d", transcripts)))
+	fmt.Println(theme.Content.Render(fmt.Sprintf("Size:        %.1f KiB", float64(size)/1024)))
+	return exitOK
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies a pre-commit hook written by revyu, so uninstall
+// never removes someone else's hook
+const hookMarker = "# installed by revyu hook install"
+
+// preCommitHook reviews the staged changes and blocks the commit on findings
+// at or above the given severity
+func preCommitHook(failOn Severity) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# Skip it once with: git commit --no-verify
+exec revyu review --staged --no-tui --fail-on %s .
+`, hookMarker, strings.ToLower(string(failOn)))
+}
+
+// runHook runs `revyu hook install|uninstall` and returns the exit code
+func runHook(args []string) int {
+	fs := newFlagSet("hook")
+	failOn := fs.String("fail-on", "high", "block the commit on findings at or above this `severity`")
+	force := fs.Bool("force", false, "replace an existing pre-commit hook")
+	usage := func() {
+		printHelp("hook install|uninstall [flags]",
+			"Installs a git pre-commit hook that reviews the staged changes and blocks\n"+
+				"the commit when there are findings at or above --fail-on.",
+			fs)
+	}
+
+	if len(args) > 0 && isHelp(args[0]) {
+		usage()
+		return exitOK
+	}
+	if len(args) == 0 || (args[0] != "install" && args[0] != "uninstall") {
+		usage()
+		return exitUsage
+	}
+	action := args[0]
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return usageError(err, usage)
+	}
+	if fs.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q", fs.Arg(0)), usage)
+	}
+	severity, err := parseSeverity(*failOn)
+	if err != nil {
+		return usageError(err, usage)
+	}
+
+	dir, err := gitHooksDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+	path := filepath.Join(dir, "pre-commit")
+
+	existing, err := os.ReadFile(path)
+	found := err == nil
+	ours := found && strings.Contains(string(existing), hookMarker)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("failed to read %s: %v", path, err)))
+		return exitError
+	}
+
+	if action == "uninstall" {
+		if !found {
+			fmt.Println(theme.Subtitle.Render("No pre-commit hook installed"))
+			return exitOK
+		}
+		if !ours && !*force {
+			fmt.Fprintln(os.Stderr, theme.Error.Render(path+" was not installed by revyu, use --force to remove it"))
+			return exitError
+		}
+		if err := os.Remove(path); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("failed to remove %s: %v", path, err)))
+			return exitError
+		}
+		fmt.Println(theme.Success.Render("Removed " + path))
+		return exitOK
+	}
+
+	if found && !ours && !*force {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(path+" already exists, use --force to replace it"))
+		return exitError
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("failed to create %s: %v", dir, err)))
+		return exitError
+	}
+	if err := os.WriteFile(path, []byte(preCommitHook(severity)), 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render(fmt.Sprintf("failed to write %s: %v", path, err)))
+		return exitError
+	}
+
+	fmt.Println(theme.Success.Render("Installed " + path))
+	return exitOK
+}
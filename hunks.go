@@ -0,0 +1,191 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// diffLine is a line of the new version of a file that appears in a diff.
+// hunk numbers the hunk it belongs to within the file; oldLine is the line in
+// the old version for context lines and 0 for added lines.
+type diffLine struct {
+	hunk    int
+	oldLine int
+	added   bool
+}
+
+// diffFile holds the commentable lines of one file in a diff
+type diffFile struct {
+	oldPath string
+	lines   map[int]diffLine
+}
+
+// parseDiffLines maps every file in a unified diff to the new-side lines that
+// appear in it. Code hosts only accept review comments on these lines.
+func parseDiffLines(diff string) map[string]*diffFile {
+	files := map[string]*diffFile{}
+	var current *diffFile
+	oldPath := ""
+	hunk, oldLine, newLine := 0, 0, 0
+	// Lines still expected in the current hunk, so that a removed line that
+	// starts with "--" is not mistaken for a file header
+	oldLeft, newLeft := 0, 0
+
+	for _, line := range strings.Split(diff, "\n") {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if current != nil {
+					current.lines[newLine] = diffLine{hunk: hunk, added: true}
+				}
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLine++
+				oldLeft--
+			case strings.HasPrefix(line, " ") || line == "":
+				if current != nil {
+					current.lines[newLine] = diffLine{hunk: hunk, oldLine: oldLine}
+				}
+				oldLine++
+				newLine++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = nil
+			oldPath = ""
+			hunk = 0
+		case strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			path := diffPath(strings.TrimPrefix(line, "+++ "), "b/")
+			if path == "/dev/null" {
+				current = nil
+				continue
+			}
+			if oldPath == "/dev/null" {
+				oldPath = path
+			}
+			current = &diffFile{oldPath: oldPath, lines: map[int]diffLine{}}
+			files[path] = current
+			hunk = 0
+		case strings.HasPrefix(line, "@@"):
+			hunk++
+			oldLine, oldLeft, newLine, newLeft = parseHunkHeader(line)
+		}
+	}
+
+	return files
+}
+
+// diffPath strips the a/ or b/ prefix and any trailing timestamp from a file
+// header path
+func diffPath(path, prefix string) string {
+	if idx := strings.Index(path, "\t"); idx >= 0 {
+		path = path[:idx]
+	}
+
+	return strings.TrimPrefix(path, prefix)
+}
+
+// parseHunkHeader reads the line numbers and counts from a hunk header such
+// as "@@ -10,6 +12,8 @@". A missing count means one line.
+func parseHunkHeader(header string) (oldStart, oldCount, newStart, newCount int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0, 0, 0
+	}
+
+	parse := func(field string) (int, int) {
+		field = strings.TrimLeft(field, "-+")
+		count := 1
+		if idx := strings.Index(field, ","); idx >= 0 {
+			count, _ = strconv.Atoi(field[idx+1:])
+			field = field[:idx]
+		}
+		start, _ := strconv.Atoi(field)
+		return start, count
+	}
+
+	oldStart, oldCount = parse(fields[1])
+	newStart, newCount = parse(fields[2])
+	return oldStart, oldCount, newStart, newCount
+}
+
+// commentRange returns the lines of a finding that can be commented on in the
+// diff. A range is kept only when both ends fall in the same hunk; otherwise
+// the comment is placed on a single line. ok is false when the finding is not
+// part of the diff at all.
+func commentRange(files map[string]*diffFile, item ReviewItem) (start, end int, ok bool) {
+	file, found := files[item.file]
+	if !found || item.startLine == 0 {
+		return 0, 0, false
+	}
+
+	first, inDiff := file.lines[item.startLine]
+	if item.endLine > item.startLine {
+		last, lastInDiff := file.lines[item.endLine]
+		switch {
+		case inDiff && lastInDiff && first.hunk == last.hunk:
+			return item.startLine, item.endLine, true
+		case lastInDiff:
+			return item.endLine, item.endLine, true
+		}
+	}
+	if inDiff {
+		return item.startLine, item.startLine, true
+	}
+
+	return 0, 0, false
+}
+
+// diffHunk returns the hunk of file in the diff that contains the given line
+// of the new version, or "" when the line is not part of the diff
+func diffHunk(diff, file string, line int) string {
+	if file == "" || line == 0 {
+		return ""
+	}
+
+	var hunk []string
+	inFile, found := false, false
+	newLine, newLeft, oldLeft := 0, 0, 0
+	for _, l := range strings.Split(diff, "\n") {
+		if oldLeft > 0 || newLeft > 0 {
+			if inFile {
+				hunk = append(hunk, l)
+			}
+			switch {
+			case strings.HasPrefix(l, "+"):
+				found = found || (inFile && newLine == line)
+				newLine++
+				newLeft--
+			case strings.HasPrefix(l, "-"):
+				oldLeft--
+			case strings.HasPrefix(l, " ") || l == "":
+				found = found || (inFile && newLine == line)
+				newLine++
+				oldLeft--
+				newLeft--
+			}
+			if found && oldLeft <= 0 && newLeft <= 0 {
+				return strings.Join(hunk, "\n")
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(l, "+++ "):
+			inFile = diffPath(strings.TrimPrefix(l, "+++ "), "b/") == file
+		case strings.HasPrefix(l, "@@"):
+			_, oldLeft, newLine, newLeft = parseHunkHeader(l)
+			hunk = []string{l}
+		}
+	}
+
+	return ""
+}
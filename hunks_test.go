@@ -0,0 +1,120 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testDiff has a removed line that starts with "--" and an added line that
+// starts with "++", a second hunk, a new file and a deleted file
+const testDiff = `diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -1,4 +1,4 @@
+ package a
+--- x
++++ y
+ func a() {}
+ // end
+@@ -20,2 +20,3 @@ func b() {
+ x := 1
++y := 2
+ return
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package b
++
+diff --git a/old.go b/old.go
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package c
+`
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		header                                 string
+		oldStart, oldCount, newStart, newCount int
+	}{
+		{"@@ -10,6 +12,8 @@", 10, 6, 12, 8},
+		{"@@ -1 +1 @@", 1, 1, 1, 1},
+		{"@@ -0,0 +1,2 @@ func main() {", 0, 0, 1, 2},
+		{"@@ -5,3 +4,0 @@", 5, 3, 4, 0},
+		{"@@", 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		oldStart, oldCount, newStart, newCount := parseHunkHeader(tt.header)
+		if oldStart != tt.oldStart || oldCount != tt.oldCount || newStart != tt.newStart || newCount != tt.newCount {
+			t.Errorf("parseHunkHeader(%q) = %d,%d %d,%d; want %d,%d %d,%d", tt.header,
+				oldStart, oldCount, newStart, newCount, tt.oldStart, tt.oldCount, tt.newStart, tt.newCount)
+		}
+	}
+}
+
+func TestParseDiffLines(t *testing.T) {
+	files := parseDiffLines(testDiff)
+
+	want := map[string]*diffFile{
+		"a.go": {oldPath: "a.go", lines: map[int]diffLine{
+			1:  {hunk: 1, oldLine: 1},
+			2:  {hunk: 1, added: true},
+			3:  {hunk: 1, oldLine: 3},
+			4:  {hunk: 1, oldLine: 4},
+			20: {hunk: 2, oldLine: 20},
+			21: {hunk: 2, added: true},
+			22: {hunk: 2, oldLine: 21},
+		}},
+		"new.go": {oldPath: "new.go", lines: map[int]diffLine{
+			1: {hunk: 1, added: true},
+			2: {hunk: 1, added: true},
+		}},
+	}
+
+	if !reflect.DeepEqual(files, want) {
+		for path, file := range files {
+			t.Logf("%s: %+v", path, *file)
+		}
+		t.Errorf("parseDiffLines did not return the expected files")
+	}
+}
+
+func TestCommentRange(t *testing.T) {
+	files := parseDiffLines(testDiff)
+
+	tests := []struct {
+		name       string
+		item       ReviewItem
+		start, end int
+		ok         bool
+	}{
+		{"single added line", ReviewItem{file: "a.go", startLine: 21}, 21, 21, true},
+		{"context line", ReviewItem{file: "a.go", startLine: 3}, 3, 3, true},
+		{"range in one hunk", ReviewItem{file: "a.go", startLine: 1, endLine: 4}, 1, 4, true},
+		{"range across hunks", ReviewItem{file: "a.go", startLine: 3, endLine: 21}, 21, 21, true},
+		{"range ending outside the diff", ReviewItem{file: "a.go", startLine: 20, endLine: 30}, 20, 20, true},
+		{"range starting outside the diff", ReviewItem{file: "a.go", startLine: 10, endLine: 20}, 20, 20, true},
+		{"range outside the diff", ReviewItem{file: "a.go", startLine: 8, endLine: 12}, 0, 0, false},
+		{"line between hunks", ReviewItem{file: "a.go", startLine: 10}, 0, 0, false},
+		{"new file", ReviewItem{file: "new.go", startLine: 1, endLine: 2}, 1, 2, true},
+		{"deleted file", ReviewItem{file: "old.go", startLine: 1}, 0, 0, false},
+		{"file not in the diff", ReviewItem{file: "x", startLine: 1}, 0, 0, false},
+		{"no line", ReviewItem{file: "a.go"}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := commentRange(files, tt.item)
+			if start != tt.start || end != tt.end || ok != tt.ok {
+				t.Errorf("commentRange = %d, %d, %v; want %d, %d, %v", start, end, ok, tt.start, tt.end, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// ignoreMatch reports whether a repo-relative file path matches an ignore
+// pattern. Patterns without a slash match the file name anywhere, like
+// "*.lock"; "dir/" and "dir/**" match everything below a directory; a leading
+// "**/" matches at any depth; other patterns match the whole path.
+func ignoreMatch(file, pattern string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		pattern = dir + "/"
+	}
+
+	if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+		if strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+		// A bare directory name such as "vendor/" matches at any depth
+		return !strings.Contains(dir, "/") && strings.Contains("/"+file, "/"+dir+"/")
+	}
+
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(file))
+		return matched
+	}
+
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		parts := strings.Split(file, "/")
+		for i := range parts {
+			if ignoreMatch(strings.Join(parts[i:], "/"), rest) {
+				return true
+			}
+		}
+		return false
+	}
+
+	matched, _ := path.Match(pattern, file)
+	return matched
+}
+
+// isIgnored reports whether any pattern matches the file
+func isIgnored(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ignoreMatch(file, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffSectionPath returns the file a "diff --git a/x b/y" header is about
+func diffSectionPath(header string) string {
+	fields := strings.Fields(strings.TrimPrefix(header, "diff --git "))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(fields[len(fields)-1], "b/")
+}
+
+// filterDiff drops the files matching the ignore patterns from a git diff
+// and returns the remaining diff with the included and excluded file names
+func filterDiff(diff string, patterns []string) (string, []string, []string) {
+	var b strings.Builder
+	included, excluded := []string{}, []string{}
+	keep := true
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			file := diffSectionPath(strings.TrimSpace(line))
+			keep = !isIgnored(file, patterns)
+			if keep {
+				included = append(included, file)
+			} else {
+				excluded = append(excluded, file)
+			}
+		}
+		if keep {
+			b.WriteString(line)
+		}
+	}
+
+	return b.String(), included, excluded
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	tests := []struct {
+		file    string
+		pattern string
+		want    bool
+	}{
+		{"go.sum", "*.sum", true},
+		{"sub/go.sum", "*.sum", true},
+		{"go.mod", "*.sum", false},
+		{"vendor/a/b.go", "vendor/", true},
+		{"lib/vendor/b.go", "vendor/", true},
+		{"vendors/b.go", "vendor/", false},
+		{"vendor/a/b.go", "vendor/**", true},
+		{"web/dist/app.js", "web/dist/", true},
+		{"other/web/dist/app.js", "web/dist/", false},
+		{"a/testdata/x.golden", "**/testdata/*.golden", true},
+		{"testdata/x.golden", "**/testdata/*.golden", true},
+		{"a/testdata/x.go", "**/testdata/*.golden", false},
+		{"docs/a.md", "docs/*.md", true},
+		{"docs/sub/a.md", "docs/*.md", false},
+		{"docs/a.md", "./docs/*.md", true},
+		{"main.go", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := ignoreMatch(tt.file, tt.pattern); got != tt.want {
+			t.Errorf("ignoreMatch(%q, %q) = %v, want %v", tt.file, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestFilterDiff(t *testing.T) {
+	mainSection := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n"
+	sumSection := "diff --git a/go.sum b/go.sum\n--- a/go.sum\n+++ b/go.sum\n@@ -1 +1 @@\n-x\n+y\n"
+	vendorSection := "diff --git a/vendor/lib.go b/vendor/lib.go\n--- a/vendor/lib.go\n+++ b/vendor/lib.go\n@@ -1 +1 @@\n-c\n+d\n"
+	diff := mainSection + sumSection + vendorSection
+
+	tests := []struct {
+		name     string
+		patterns []string
+		diff     string
+		included []string
+		excluded []string
+	}{
+		{"no patterns", nil, diff, []string{"main.go", "go.sum", "vendor/lib.go"}, []string{}},
+		{"lock files and vendor", []string{"*.sum", "vendor/"}, mainSection, []string{"main.go"}, []string{"go.sum", "vendor/lib.go"}},
+		{"middle file", []string{"go.sum"}, mainSection + vendorSection, []string{"main.go", "vendor/lib.go"}, []string{"go.sum"}},
+		{"everything", []string{"*"}, "", []string{}, []string{"main.go", "go.sum", "vendor/lib.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, included, excluded := filterDiff(diff, tt.patterns)
+			if got != tt.diff {
+				t.Errorf("diff =\n%s\nwant\n%s", got, tt.diff)
+			}
+			if !reflect.DeepEqual(included, tt.included) || !reflect.DeepEqual(excluded, tt.excluded) {
+				t.Errorf("included %q, excluded %q; want %q, %q", included, excluded, tt.included, tt.excluded)
+			}
+		})
+	}
+}
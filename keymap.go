@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap holds every named action the TUI responds to. Keys can be remapped
+// through the "keys" section of the config file.
+type keyMap struct {
+	Up             key.Binding
+	Down           key.Binding
+	Toggle         key.Binding
+	CheckAll       key.Binding
+	UncheckAll     key.Binding
+	Search         key.Binding
+	FilterSeverity key.Binding
+	FilterChecked  key.Binding
+	FilterFile     key.Binding
+	FilterCategory key.Binding
+	ClearFilters   key.Binding
+	Group          key.Binding
+	Sort           key.Binding
+	Collapse       key.Binding
+	NextGroup      key.Binding
+	PrevGroup      key.Binding
+	Ask            key.Binding
+	AskDiff        key.Binding
+	Dismiss        key.Binding
+	Dismissed      key.Binding
+	Undismiss      key.Binding
+	Edit           key.Binding
+	CopyItem       key.Binding
+	CopyCode       key.Binding
+	CopyAll        key.Binding
+	Rerun          key.Binding
+	Send           key.Binding
+	Back           key.Binding
+	Help           key.Binding
+	Quit           key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:             key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:           key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		Toggle:         key.NewBinding(key.WithKeys(" ", "x"), key.WithHelp("space/x", "toggle")),
+		CheckAll:       key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "check all")),
+		UncheckAll:     key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "uncheck all")),
+		Search:         key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		FilterSeverity: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "severity")),
+		FilterChecked:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "checked")),
+		FilterFile:     key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "file")),
+		FilterCategory: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "category")),
+		ClearFilters:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear filters")),
+		Group:          key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "group")),
+		Sort:           key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "sort")),
+		Collapse:       key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "collapse group")),
+		NextGroup:      key.NewBinding(key.WithKeys("]", "tab"), key.WithHelp("]", "next group")),
+		PrevGroup:      key.NewBinding(key.WithKeys("[", "shift+tab"), key.WithHelp("[", "previous group")),
+		Ask:            key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "ask about item")),
+		AskDiff:        key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "ask about diff")),
+		Dismiss:        key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "dismiss")),
+		Dismissed:      key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "dismissed list")),
+		Undismiss:      key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "un-dismiss")),
+		Edit:           key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "open in editor")),
+		CopyItem:       key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy finding")),
+		CopyCode:       key.NewBinding(key.WithKeys("Y"), key.WithHelp("Y", "copy code")),
+		CopyAll:        key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "copy checklist")),
+		Rerun:          key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "re-run review")),
+		Send:           key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "send")),
+		Back:           key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		Help:           key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Quit:           key.NewBinding(key.WithKeys("q", "enter"), key.WithHelp("enter/q", "quit")),
+	}
+}
+
+// bindings maps the config names of actions to their bindings
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":              &k.Up,
+		"down":            &k.Down,
+		"toggle":          &k.Toggle,
+		"check_all":       &k.CheckAll,
+		"uncheck_all":     &k.UncheckAll,
+		"search":          &k.Search,
+		"filter_severity": &k.FilterSeverity,
+		"filter_checked":  &k.FilterChecked,
+		"filter_file":     &k.FilterFile,
+		"filter_category": &k.FilterCategory,
+		"clear_filters":   &k.ClearFilters,
+		"group":           &k.Group,
+		"sort":            &k.Sort,
+		"collapse":        &k.Collapse,
+		"next_group":      &k.NextGroup,
+		"prev_group":      &k.PrevGroup,
+		"ask":             &k.Ask,
+		"ask_diff":        &k.AskDiff,
+		"dismiss":         &k.Dismiss,
+		"dismissed_list":  &k.Dismissed,
+		"undismiss":       &k.Undismiss,
+		"edit":            &k.Edit,
+		"copy_item":       &k.CopyItem,
+		"copy_code":       &k.CopyCode,
+		"copy_all":        &k.CopyAll,
+		"rerun":           &k.Rerun,
+		"send":            &k.Send,
+		"back":            &k.Back,
+		"help":            &k.Help,
+		"quit":            &k.Quit,
+	}
+}
+
+// newKeyMap applies the user's overrides on top of the default bindings.
+// Help text is rebuilt from the new keys so the footer stays accurate.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	keys := defaultKeyMap()
+	bindings := keys.bindings()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		binding, ok := bindings[name]
+		if !ok {
+			return keys, fmt.Errorf("unknown key action %q in config", name)
+		}
+		if len(overrides[name]) == 0 {
+			return keys, fmt.Errorf("key action %q has no keys", name)
+		}
+
+		labels := make([]string, len(overrides[name]))
+		for i, k := range overrides[name] {
+			labels[i] = k
+			if k == " " {
+				labels[i] = "space"
+			}
+		}
+		binding.SetKeys(overrides[name]...)
+		binding.SetHelp(strings.Join(labels, "/"), binding.Help().Desc)
+	}
+
+	return keys, nil
+}
+
+// checklistHelp lists the checklist actions, one group per footer line
+func (k keyMap) checklistHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Toggle, k.CheckAll, k.UncheckAll, k.Quit},
+		{k.Search, k.FilterSeverity, k.FilterChecked, k.FilterFile, k.FilterCategory, k.ClearFilters},
+		{k.Group, k.Sort, k.Collapse, k.PrevGroup, k.NextGroup},
+		{k.Ask, k.AskDiff, k.Dismiss, k.Dismissed, k.Edit},
+		{k.CopyItem, k.CopyCode, k.CopyAll, k.Rerun, k.Help},
+	}
+}
+
+func (k keyMap) chatHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Send, k.Back, k.Help},
+	}
+}
+
+func (k keyMap) suppressionsHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Undismiss, k.Back, k.Help},
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// debugLog records what revyu sends and receives. It discards everything
+// unless --debug or --log-file is given.
+var debugLog = slog.New(slog.DiscardHandler)
+
+// debugLogFile is the name of the log written during TUI sessions when
+// --debug is given without --log-file
+const debugLogFile = "debug.log"
+
+var (
+	logSecretsMu sync.Mutex
+	logSecrets   []string
+)
+
+// addLogSecret makes the log mask a credential wherever it appears
+func addLogSecret(secret string) {
+	if strings.TrimSpace(secret) == "" {
+		return
+	}
+
+	logSecretsMu.Lock()
+	defer logSecretsMu.Unlock()
+	logSecrets = append(logSecrets, secret)
+}
+
+// maskSecrets replaces registered credentials and anything that looks like
+// one with a masked form
+func maskSecrets(text string) string {
+	logSecretsMu.Lock()
+	for _, secret := range logSecrets {
+		text = strings.ReplaceAll(text, secret, maskKey(secret))
+	}
+	logSecretsMu.Unlock()
+
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllStringFunc(text, maskKey)
+	}
+
+	return text
+}
+
+// maskingHandler masks secrets in every string attribute before the record
+// is written
+type maskingHandler struct {
+	slog.Handler
+}
+
+func (h maskingHandler) Handle(ctx context.Context, r slog.Record) error {
+	masked := slog.NewRecord(r.Time, r.Level, maskSecrets(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		masked.AddAttrs(maskAttr(a))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, masked)
+}
+
+func (h maskingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for i := range attrs {
+		attrs[i] = maskAttr(attrs[i])
+	}
+
+	return maskingHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h maskingHandler) WithGroup(name string) slog.Handler {
+	return maskingHandler{h.Handler.WithGroup(name)}
+}
+
+// maskAttr masks string values, including those inside groups and errors
+func maskAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, maskSecrets(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		masked := make([]any, len(group))
+		for i, attr := range group {
+			masked[i] = maskAttr(attr)
+		}
+		return slog.Group(a.Key, masked...)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, maskSecrets(err.Error()))
+		}
+	}
+
+	return a
+}
+
+// startLogging installs the debug logger described by the config. Logs go
+// to log_file when set and to stderr otherwise. It returns a function that
+// closes the log file.
+func startLogging(cfg Config) (func(), error) {
+	if !cfg.Debug && cfg.LogFile == "" {
+		return func() {}, nil
+	}
+
+	var (
+		out      io.Writer = os.Stderr
+		closeLog           = func() {}
+	)
+	if cfg.LogFile != "" {
+		if dir := filepath.Dir(cfg.LogFile); dir != "." {
+			if err := os.MkdirAll(dir, 0o700); err != nil {
+				return nil, fmt.Errorf("failed to create %s: %v", dir, err)
+			}
+		}
+		// Logs hold prompts and code, so keep them private
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %v", err)
+		}
+		out = file
+		closeLog = func() { file.Close() }
+	}
+
+	handler := slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})
+	debugLog = slog.New(maskingHandler{handler})
+	debugLog.Debug("start", "version", version, "args", strings.Join(os.Args[1:], " "))
+
+	return closeLog, nil
+}
+
+// defaultLogPath returns where TUI sessions log when only --debug is given,
+// since stderr would garble the screen
+func defaultLogPath() string {
+	if dir, err := cacheDir(); err == nil {
+		return filepath.Join(dir, debugLogFile)
+	}
+
+	return filepath.Join(os.TempDir(), "revyu-"+debugLogFile)
+}
@@ -4,62 +4,133 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/joho/godotenv"
 )
 
-var buildTimeAPIKey string
-
 func main() {
-	apiKey := buildTimeAPIKey
+	os.Exit(dispatch(os.Args[1:]))
+}
+
+// runReviewCommand runs `revyu review` and returns the exit code
+func runReviewCommand(args []string) int {
+	cli, err := parseArgs(args)
+	if err != nil {
+		return usageError(err, printReviewUsage)
+	}
+
+	cfg, _, keys, ok := loadSettings(cli.settings)
+	if !ok {
+		return exitError
+	}
+	cli.format = cfg.Format
+
+	if cfg.Debug && cfg.LogFile == "" && cli.interactive() {
+		// stderr would garble the TUI
+		cfg.LogFile = defaultLogPath()
+	}
+	closeLog, err := startLogging(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+		return exitError
+	}
+	defer closeLog()
+
+	filePath := cli.target
 
-	if checkEmpty(apiKey) {
-		apiKey = os.Getenv("OPENAI_API_KEY")
+	var (
+		diff     string
+		patches  []patch
+		prepared preparedDiff
+	)
+	if cli.patches != nil {
+		patches, err = loadPatches(cli.patches, os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error.Render("Error reading patches"))
+			fmt.Fprintln(os.Stderr, theme.Content.Render(err.Error()))
+			return exitError
+		}
+		patches, prepared = preparePatches(patches, cfg.Ignore, cfg.Redact)
+	} else {
+		diff, err = getGitDiff(filePath, cli.staged)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error.Render("Error getting git diff"))
+			fmt.Fprintln(os.Stderr, theme.Content.Render(err.Error()))
+			return exitError
+		}
+		prepared = prepareDiff(diff, cfg.Ignore, cfg.Redact)
+	}
+	diff = prepared.diff
+
+	if cli.dryRun {
+		// No credentials either: a credential helper could reach the network
+		if err := cfg.reviewOptions().validate(); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error.Render("Error: "+err.Error()))
+			return exitError
+		}
+		return printDryRun(cli, newDryRun(cfg.reviewOptions(), prepared, patches))
+	}
+
+	apiKey, ok := requireAPIKey(cfg)
+	if !ok {
+		return exitError
 	}
 
-	if checkEmpty(apiKey) {
-		err := godotenv.Load()
-		if err == nil {
-			apiKey = os.Getenv("OPENAI_API_KEY")
+	if checkEmpty(strings.TrimSpace(diff)) {
+		if cli.format != "text" {
+			// Machine-readable output stays parseable with an empty report
+			run := reviewRun{target: filePath, diff: diff, opts: cfg.reviewOptions(), started: time.Now()}
+			run.base, run.head = diffRevisions(filePath, cli.staged, cli.patches != nil)
+			return printRun(cli, run)
 		}
+		fmt.Println(theme.Subtitle.Render("No changes detected in git diff"))
+		return exitOK
 	}
 
-	if checkEmpty(apiKey) {
-		fmt.Println(errorStyle.Render("❌ Error: OPENAI_API_KEY not found"))
-		fmt.Println(contentStyle.Render("Please either:"))
-		fmt.Println(contentStyle.Render("  1. Set OPENAI_API_KEY environment variable"))
-		fmt.Println(contentStyle.Render("  2. Create a .env file with your OpenAI API key"))
-		fmt.Println(contentStyle.Render("  3. Build with embedded key using: ./build.sh"))
-		os.Exit(1)
+	if !cli.interactive() {
+		return runPlain(apiKey, cli, cfg.reviewOptions(), diff, patches)
 	}
 
-	if len(os.Args) < 2 {
-		fmt.Println(titleStyle.Render("Revyu - AI-Powered Code Review TESTING"))
-		fmt.Println()
-		fmt.Println(subtitleStyle.Render("Usage:"))
-		fmt.Println(contentStyle.Render("  revyu <filename>  - Review git diff for a specific file"))
-		fmt.Println(contentStyle.Render("  revyu .           - Review git diff for all tracked files"))
-		os.Exit(1)
+	opts := []tea.ProgramOption{}
+	if !cfg.DisableMouse {
+		// Clicks report screen rows, which only match the view when it fills
+		// the alternate screen instead of scrolling inline
+		opts = append(opts, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	}
 
-	filePath := os.Args[1]
+	m := initialModel(apiKey, filePath, diff, keys)
+	m.staged = cli.staged
+	m.confirmQuit = cfg.ConfirmQuit
+	m.opts = cfg.reviewOptions()
+	m.ignore = cfg.Ignore
+	m.redact = cfg.Redact
+	m.patches = patches
+	if len(patches) > 1 {
+		m.grouping = groupByPatch
+	}
 
-	diff, err := getGitDiff(filePath)
+	p := tea.NewProgram(m, opts...)
+	final, err := p.Run()
 	if err != nil {
-		fmt.Println(errorStyle.Render("Error getting git diff"))
-		fmt.Println(contentStyle.Render(err.Error()))
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, theme.Error.Render("Error running program: "+err.Error()))
+		return exitError
 	}
 
-	if checkEmpty(strings.TrimSpace(diff)) {
-		fmt.Println(subtitleStyle.Render("No changes detected in git diff"))
-		os.Exit(0)
+	if cfg.LogFile != "" {
+		defer fmt.Println(theme.Subtitle.Render("Debug log written to " + cfg.LogFile))
 	}
 
-	p := tea.NewProgram(initialModel(apiKey, filePath, diff))
-	if _, err := p.Run(); err != nil {
-		fmt.Println(errorStyle.Render("Error running program: " + err.Error()))
-		os.Exit(1)
+	if fm, ok := final.(model); ok && fm.review != "" {
+		fmt.Print(exitSummary(fm))
+		if cli.output != "" {
+			if err := writeReport(cli.output, fm.reportRun()); err != nil {
+				fmt.Fprintln(os.Stderr, theme.Error.Render("Error writing report: "+err.Error()))
+				return exitError
+			}
+			fmt.Println(theme.Subtitle.Render("Report written to " + cli.output))
+		}
 	}
+
+	return exitOK
 }
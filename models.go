@@ -1,6 +1,9 @@
 package main
 
 import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/spinner"
 )
 
@@ -14,13 +17,75 @@ const (
 	SeverityLow    Severity = "Low"
 )
 
+// Category tells whether an item came from the issues or suggestions section
+type Category string
+
+const (
+	CategoryIssue      Category = "Issue"
+	CategorySuggestion Category = "Suggestion"
+)
+
 type ReviewItem struct {
 	number     int
 	title      string
 	content    string
 	codeBlocks []string
 	severity   Severity
+	category   Category
+	file       string
+	startLine  int
+	endLine    int
 	checked    bool
+	dismissed  bool
+	// patch is "2/5: subject" for findings in the review of a patch series
+	patch string
+}
+
+// checkedFilter restricts the checklist to checked or unchecked items
+type checkedFilter int
+
+const (
+	checkedAll checkedFilter = iota
+	checkedOnly
+	uncheckedOnly
+)
+
+// filterState holds the active search query and filter toggles
+type filterState struct {
+	query    string
+	severity Severity
+	checked  checkedFilter
+	file     string
+	category Category
+}
+
+// groupMode controls how the checklist is split into collapsible groups
+type groupMode int
+
+const (
+	groupNone groupMode = iota
+	groupByFile
+	groupBySeverity
+	groupByCategory
+	groupByPatch
+)
+
+// sortOrder controls the order of items inside the checklist or each group
+type sortOrder int
+
+const (
+	sortOriginal sortOrder = iota
+	sortSeverityDesc
+	sortFileLine
+)
+
+// listRow is one selectable line of the checklist: either a group header
+// (item is -1) or an index into model.items
+type listRow struct {
+	group   string
+	item    int
+	count   int
+	checked int
 }
 
 // model is the state for the TUI
@@ -32,16 +97,71 @@ type model struct {
 	diff      string
 	filePath  string
 	apiKey    string
+	opts      reviewOptions
 	quitting  bool
 	items     []ReviewItem
 	cursorPos int
-	width     int
-	height    int
+	scroll    int
+	filter    filterState
+	searching bool
+	grouping  groupMode
+	sorting   sortOrder
+	collapsed map[string]bool
+	chatOpen  bool
+	chatInput string
+	chatFocus int
+	chatLog   []Message
+	chatBusy  bool
+	chatErr   error
+
+	suppressions   []Suppression
+	dismissing     bool
+	showSuppressed bool
+	suppressCursor int
+	suppressErr    error
+
+	offerRestore *SessionState
+	restoredAt   time.Time
+	restored     int
+	sessionErr   error
+	editorErr    error
+	status       string
+
+	rerunForm   *rerunForm
+	previousRun []SavedItem
+	comparison  []runDelta
+	ignore      []string
+	redact      bool
+	staged      bool
+	patches     []patch
+
+	confirmQuit bool
+	confirming  bool
+
+	keys     keyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
 }
 
-// reviewMsg is sent when the review is complete
+// reviewMsg is sent when the review is complete. restored is set when the
+// review was loaded from a saved session for the same diff, previous when an
+// older session for the same target could be re-associated. A re-run from
+// the TUI also carries the new target and the diff that was reviewed.
 type reviewMsg struct {
-	review string
+	review   string
+	err      error
+	restored *SessionState
+	previous *SessionState
+	rerun    bool
+	target   string
+	diff     string
+}
+
+// chatMsg is sent when a follow-up question has been answered
+type chatMsg struct {
+	answer string
 	err    error
 }
 
@@ -60,8 +180,16 @@ type OpenAIResponse struct {
 	Choices []struct {
 		Message Message `json:"message"`
 	} `json:"choices"`
+	Usage Usage `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error"`
 }
+
+// Usage is the token count the API reports for a request
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
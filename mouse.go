@@ -0,0 +1,74 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Columns of the cursor marker and checkbox on an item's first line, counted
+// from the left edge of the terminal past the box border and padding
+const (
+	checkboxStart = 3
+	checkboxEnd   = 8
+)
+
+// wheelStep is how many lines one wheel notch scrolls the checklist
+const wheelStep = 3
+
+// updateMouse handles clicks and wheel events on the checklist. Events are
+// ignored while an overlay covers it.
+func (m model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.loading || m.chatOpen || m.showSuppressed || m.showHelp || m.searching || m.dismissing || m.rerunForm != nil || m.confirming {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.scrollBy(-wheelStep)
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		m.scrollBy(wheelStep)
+		return m, nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+
+	line := msg.Y - m.bodyTop()
+	if line < 0 || line >= m.bodyHeight() {
+		return m, nil
+	}
+	line += m.scroll
+
+	rows := m.rows()
+	_, starts := m.checklistLines(m.maxWidth())
+	rowIdx := -1
+	for i, start := range starts {
+		if start > line {
+			break
+		}
+		rowIdx = i
+	}
+	if rowIdx < 0 || rowIdx >= len(rows) {
+		return m, nil
+	}
+
+	m.cursorPos = rowIdx
+	row := rows[rowIdx]
+	offset := line - starts[rowIdx]
+
+	switch {
+	case row.item == -1:
+		m.toggleCollapse(rows)
+		m.clampCursor()
+	case offset == 0 && msg.X >= checkboxStart && msg.X < checkboxEnd:
+		m.items[row.item].checked = !m.items[row.item].checked
+		m.sessionErr = m.saveSession()
+	case offset == 1:
+		return m, m.openInEditor(m.items[row.item])
+	}
+
+	return m, nil
+}
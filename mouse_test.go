@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewRow returns the screen row of the first rendered line containing text
+func viewRow(t *testing.T, m model, text string) int {
+	t.Helper()
+	for y, line := range strings.Split(m.View(), "\n") {
+		if strings.Contains(line, text) {
+			return y
+		}
+	}
+	t.Fatalf("%q is not in the view:\n%s", text, m.View())
+	return -1
+}
+
+func click(m model, x, y int) model {
+	next, _ := m.updateMouse(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	return next.(model)
+}
+
+func TestClickCheckboxMatchesView(t *testing.T) {
+	initTestRepo(t)
+
+	tests := []struct {
+		name   string
+		scroll int
+		height int
+	}{
+		{"top of the list", 0, 40},
+		{"scrolled", 3, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel()
+			m.width, m.height = 100, tt.height
+			m.scroll = tt.scroll
+
+			y := viewRow(t, m, "#2")
+			m = click(m, checkboxStart+1, y)
+			if !m.items[1].checked || m.items[0].checked {
+				t.Errorf("clicking the checkbox on row %d checked %v, %v; want only #2", y, m.items[0].checked, m.items[1].checked)
+			}
+			if m.cursorPos != 1 {
+				t.Errorf("cursor = %d, want 1", m.cursorPos)
+			}
+		})
+	}
+}
+
+func TestClickOutsideChecklist(t *testing.T) {
+	m := testModel()
+	m = click(m, checkboxStart+1, viewRow(t, m, "Revyu"))
+	for _, item := range m.items {
+		if item.checked {
+			t.Errorf("a click on the title checked #%d", item.number)
+		}
+	}
+}
+
+func TestClickIgnoredUnderOverlay(t *testing.T) {
+	m := testModel()
+	y := viewRow(t, m, "#1")
+	m.rerunForm = &rerunForm{}
+	m = click(m, checkboxStart+1, y)
+	if m.items[0].checked {
+		t.Errorf("a click reached the checklist behind the re-run form")
+	}
+}
@@ -7,15 +7,49 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"text/template"
 	"time"
 )
 
-func reviewDiff(apiKey, diff string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
+// openAIEndpoint is the chat completions API every request is sent to
+const openAIEndpoint = "https://api.openai.com/v1/chat/completions"
+
+func reviewDiff(apiKey string, opts reviewOptions, diff string) (string, Usage, error) {
+	return reviewPatch(apiKey, opts, patch{diff: diff})
+}
 
-	prompt := fmt.Sprintf(`You are an expert code reviewer. Please review the following git diff and provide a detailed analysis.
+// reviewPatch reviews one patch, giving the model its commit message when
+// there is one
+func reviewPatch(apiKey string, opts reviewOptions, p patch) (string, Usage, error) {
+	if err := opts.validate(); err != nil {
+		return "", Usage{}, err
+	}
+
+	start := time.Now()
+	prompt := buildReviewPrompt(p.diff, p.message, opts.Profile)
+	debugLog.Debug("prompt",
+		"provider", opts.Provider,
+		"model", opts.Model,
+		"profile", opts.Profile,
+		"subject", p.subject,
+		"chars", len(prompt),
+		"build_duration", time.Since(start),
+		"text", prompt)
+
+	return chatCompletion(apiKey, opts.Model, []Message{
+		{
+			Role:    "user",
+			Content: prompt,
+		},
+	})
+}
 
+// defaultPromptTemplate is the review prompt unless the config sets
+// prompt_template. {{.Focus}} is replaced by the profile's instructions,
+// {{.Message}} by the commit message of a patch and {{.Diff}} by the diff.
+const defaultPromptTemplate = `You are an expert code reviewer. Please review the following git diff and provide a detailed analysis.
+{{.Focus}}
 For each point you make, please:
 - Reference the specific file and approximate line numbers (e.g., "main.go:45-50")
 - Include relevant code snippets using markdown code blocks with language syntax
@@ -48,59 +82,123 @@ Please structure your review with these sections:
 Use markdown code blocks with proper language syntax highlighting.
 Use file references in the format: 📄 filename.ext:lineNumber
 
-Here's the git diff:
+{{if .Message}}The author describes the change with this commit message. Check that the code does what it says:
 
-%s
+{{.Message}}
 
-Please provide a comprehensive review with specific file references and code examples.`, diff)
+{{end}}Here's the git diff:
+
+{{.Diff}}
+
+Please provide a comprehensive review with specific file references and code examples.`
+
+// promptTemplate renders the review prompt
+var promptTemplate = template.Must(template.New("prompt").Parse(defaultPromptTemplate))
+
+// setPromptTemplate replaces the review prompt with a custom template
+func setPromptTemplate(text string) error {
+	t, err := template.New("prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid prompt_template: %v", err)
+	}
+	if !strings.Contains(text, ".Diff") {
+		return fmt.Errorf("invalid prompt_template: it must include {{.Diff}}")
+	}
+
+	promptTemplate = t
+	return nil
+}
+
+// buildReviewPrompt renders the review instructions around the diff and its
+// commit message, adding the focus of the selected profile
+func buildReviewPrompt(diff, message, profile string) string {
+	focus := ""
+	if instructions := reviewProfiles[profile]; instructions != "" {
+		focus = "\n" + instructions + "\n"
+	}
+
+	var b strings.Builder
+	data := struct{ Focus, Message, Diff string }{focus, message, diff}
+	if err := promptTemplate.Execute(&b, data); err != nil {
+		// The template was checked when it was loaded
+		return diff
+	}
+
+	return b.String()
+}
+
+// chatCompletion sends a conversation to the OpenAI chat API and returns the
+// reply together with the tokens it used
+func chatCompletion(apiKey, model string, messages []Message) (string, Usage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
 
 	requestBody := OpenAIRequest{
-		Model: "gpt-4o",
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+		Model:    model,
+		Messages: messages,
 	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", openAIEndpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 
+	debugLog.Debug("request",
+		"endpoint", openAIEndpoint,
+		"model", model,
+		"messages", len(messages),
+		"bytes", len(jsonData))
+
+	start := time.Now()
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("OpenAI API call failed: %v", err)
+		debugLog.Debug("request failed", "error", err, "request_duration", time.Since(start))
+		return "", Usage{}, fmt.Errorf("OpenAI API call failed: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
+	requestDuration := time.Since(start)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %v", err)
+		return "", Usage{}, fmt.Errorf("failed to read response: %v", err)
 	}
-
+	debugLog.Debug("response",
+		"status", resp.Status,
+		"request_id", resp.Header.Get("X-Request-Id"),
+		"processing_ms", resp.Header.Get("Openai-Processing-Ms"),
+		"bytes", len(body),
+		"request_duration", requestDuration,
+		"body", string(body))
+
+	start = time.Now()
 	var openAIResp OpenAIResponse
 	if err := json.Unmarshal(body, &openAIResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %v", err)
+		return "", Usage{}, fmt.Errorf("failed to unmarshal response: %v", err)
 	}
+	debugLog.Debug("usage",
+		"status", resp.StatusCode,
+		"prompt_tokens", openAIResp.Usage.PromptTokens,
+		"completion_tokens", openAIResp.Usage.CompletionTokens,
+		"total_tokens", openAIResp.Usage.TotalTokens,
+		"request_duration", requestDuration,
+		"parse_duration", time.Since(start))
 
 	if openAIResp.Error != nil {
-		return "", fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
+		return "", Usage{}, fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
 	}
 
 	if len(openAIResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+		return "", Usage{}, fmt.Errorf("no response from OpenAI")
 	}
 
-	return openAIResp.Choices[0].Message.Content, nil
+	return openAIResp.Choices[0].Message.Content, openAIResp.Usage, nil
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// reviewOptions selects who reviews the diff and what the review focuses on
+type reviewOptions struct {
+	Provider string
+	Model    string
+	Profile  string
+}
+
+func defaultReviewOptions() reviewOptions {
+	return reviewOptions{
+		Provider: "openai",
+		Model:    "gpt-4o",
+		Profile:  "default",
+	}
+}
+
+// supportedProviders lists the chat APIs revyu can talk to
+var supportedProviders = []string{"openai"}
+
+// reviewProfiles add focus instructions to the review prompt
+var reviewProfiles = map[string]string{
+	"default":     "",
+	"security":    "Focus on security: injection, authentication and authorization mistakes, secrets in code, unsafe deserialization and missing input validation. Mention other problems only if they are severe.",
+	"performance": "Focus on performance: unnecessary allocations, quadratic loops, blocking calls, missing caching and inefficient queries. Mention other problems only if they are severe.",
+	"readability": "Focus on readability and maintainability: naming, function size, duplication, comments and consistency with the surrounding code.",
+}
+
+// profileNames returns the known profile names in a stable order
+func profileNames() []string {
+	names := make([]string, 0, len(reviewProfiles))
+	for name := range reviewProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// validate checks the options before any request is made
+func (o reviewOptions) validate() error {
+	found := false
+	for _, p := range supportedProviders {
+		if o.Provider == p {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown provider %q (supported: %s)", o.Provider, strings.Join(supportedProviders, ", "))
+	}
+
+	if strings.TrimSpace(o.Model) == "" {
+		return fmt.Errorf("model must not be empty")
+	}
+
+	if _, ok := reviewProfiles[o.Profile]; !ok {
+		return fmt.Errorf("unknown profile %q (available: %s)", o.Profile, strings.Join(profileNames(), ", "))
+	}
+
+	return nil
+}
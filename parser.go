@@ -1,9 +1,10 @@
 package main
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
-
-	"github.com/charmbracelet/lipgloss"
+	"time"
 )
 
 func wrapText(text string, width int) string {
@@ -35,24 +36,6 @@ func formatMarkdown(markdown string, maxWidth int) string {
 	var result strings.Builder
 	lines := strings.Split(markdown, "\n")
 
-	sectionTitleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#BD93F9")).
-		MarginTop(1).
-		MarginBottom(1)
-
-	headingStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#50FA7B"))
-
-	codeBlockStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#50FA7B")).
-		Background(lipgloss.Color("#282A36")).
-		Padding(0, 1)
-
-	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#F8F8F2"))
-
 	inCodeBlock := false
 	var codeLines []string
 
@@ -63,7 +46,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 			if inCodeBlock {
 				for _, codeLine := range codeLines {
 					result.WriteString("  ")
-					result.WriteString(codeBlockStyle.Render(codeLine))
+					result.WriteString(theme.CodeBlock.Render(codeLine))
 					result.WriteString("\n")
 				}
 				codeLines = []string{}
@@ -81,7 +64,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 		}
 
 		if strings.HasPrefix(trimmed, "---") || strings.HasPrefix(trimmed, "===") {
-			result.WriteString(separatorStyle.Render(strings.Repeat("─", maxWidth)))
+			result.WriteString(theme.Separator.Render(strings.Repeat("─", maxWidth)))
 			result.WriteString("\n")
 			continue
 		}
@@ -95,7 +78,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 			text := strings.TrimPrefix(trimmed[3:], "**")
 			text = strings.TrimSuffix(text, "**")
 			text = strings.TrimSuffix(text, ":")
-			result.WriteString(sectionTitleStyle.Render("▸ " + text))
+			result.WriteString(theme.SectionTitle.Render("▸ " + text))
 			result.WriteString("\n")
 			continue
 		}
@@ -103,7 +86,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 		if strings.HasPrefix(trimmed, "**") && strings.HasSuffix(trimmed, "**") {
 			text := strings.Trim(trimmed, "*")
 			text = strings.TrimSuffix(text, ":")
-			result.WriteString(headingStyle.Render("  • " + text))
+			result.WriteString(theme.Heading.Render("  • " + text))
 			result.WriteString("\n")
 			continue
 		}
@@ -111,7 +94,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 		if strings.HasPrefix(trimmed, "##") {
 			text := strings.TrimPrefix(trimmed, "##")
 			text = strings.TrimSpace(text)
-			result.WriteString(sectionTitleStyle.Render("▸ " + text))
+			result.WriteString(theme.SectionTitle.Render("▸ " + text))
 			result.WriteString("\n")
 			continue
 		}
@@ -119,7 +102,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 		if strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "##") {
 			text := strings.TrimPrefix(trimmed, "#")
 			text = strings.TrimSpace(text)
-			result.WriteString(sectionTitleStyle.Render("▸ " + text))
+			result.WriteString(theme.SectionTitle.Render("▸ " + text))
 			result.WriteString("\n")
 			continue
 		}
@@ -131,9 +114,9 @@ func formatMarkdown(markdown string, maxWidth int) string {
 			lines := strings.Split(wrapped, "\n")
 			for i, l := range lines {
 				if i == 0 {
-					result.WriteString(normalStyle.Render("    • " + strings.TrimSpace(l)))
+					result.WriteString(theme.Normal.Render("    • " + strings.TrimSpace(l)))
 				} else {
-					result.WriteString(normalStyle.Render("      " + strings.TrimSpace(l)))
+					result.WriteString(theme.Normal.Render("      " + strings.TrimSpace(l)))
 				}
 				result.WriteString("\n")
 			}
@@ -141,10 +124,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 		}
 
 		if strings.Contains(trimmed, "📄") {
-			fileRefStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#8BE9FD")).
-				Bold(true)
-			result.WriteString(fileRefStyle.Render("  " + trimmed))
+			result.WriteString(theme.FileRef.Bold(true).Render("  " + trimmed))
 			result.WriteString("\n")
 			continue
 		}
@@ -153,7 +133,7 @@ func formatMarkdown(markdown string, maxWidth int) string {
 		wrapped := wrapText(text, maxWidth-4)
 		lines := strings.Split(wrapped, "\n")
 		for _, l := range lines {
-			result.WriteString(normalStyle.Render("  " + strings.TrimSpace(l)))
+			result.WriteString(theme.Normal.Render("  " + strings.TrimSpace(l)))
 			result.WriteString("\n")
 		}
 	}
@@ -171,29 +151,69 @@ func cleanInlineMarkdown(text string) string {
 	return text
 }
 
+var locationPattern = regexp.MustCompile(`([\w./\\-]+\.[A-Za-z0-9]+):(\d+)(?:-(\d+))?`)
+
+// parseLocation extracts the file path and line range from a file reference
+// such as "📄 main.go:42-50". Missing line numbers are returned as zero.
+func parseLocation(ref string) (string, int, int) {
+	match := locationPattern.FindStringSubmatch(ref)
+	if match == nil {
+		return "", 0, 0
+	}
+
+	start, _ := strconv.Atoi(match[2])
+	end := start
+	if match[3] != "" {
+		end, _ = strconv.Atoi(match[3])
+	}
+
+	return match[1], start, end
+}
+
 func parseReviewIntoItems(review string) []ReviewItem {
+	start := time.Now()
 	items := []ReviewItem{}
 	lines := strings.Split(review, "\n")
 	itemNum := 1
 
 	var currentItem *ReviewItem
+	currentPatch := ""
 	inIssuesSection := false
 	inSuggestionsSection := false
 	inCodeBlock := false
 	var currentCodeBlock []string
 
-	for _, line := range lines {
+	for i, line := range lines {
+		lineNum := i + 1
 		trimmed := strings.TrimSpace(line)
 
+		if match := patchHeading.FindStringSubmatch(trimmed); match != nil {
+			// A series review holds one review per patch
+			if currentItem != nil {
+				items = append(items, *currentItem)
+				currentItem = nil
+			}
+			currentPatch = strings.TrimPrefix(trimmed, "## Patch ")
+			debugLog.Debug("parse: patch", "line", lineNum, "patch", currentPatch)
+			inIssuesSection = false
+			inSuggestionsSection = false
+			continue
+		}
+
 		if strings.Contains(trimmed, "**Issues Found**") || strings.Contains(trimmed, "3. Issues") {
+			debugLog.Debug("parse: issues section", "line", lineNum, "text", trimmed)
 			inIssuesSection = true
 			inSuggestionsSection = false
 			continue
 		} else if strings.Contains(trimmed, "**Suggestions**") || strings.Contains(trimmed, "4. Suggestions") {
+			debugLog.Debug("parse: suggestions section", "line", lineNum, "text", trimmed)
 			inIssuesSection = false
 			inSuggestionsSection = true
 			continue
 		} else if strings.HasPrefix(trimmed, "**") && strings.HasSuffix(trimmed, "**") {
+			if inIssuesSection || inSuggestionsSection {
+				debugLog.Debug("parse: section ended", "line", lineNum, "text", trimmed)
+			}
 			inIssuesSection = false
 			inSuggestionsSection = false
 			continue
@@ -217,16 +237,36 @@ func parseReviewIntoItems(review string) []ReviewItem {
 				if currentItem != nil {
 					items = append(items, *currentItem)
 				}
+				category := CategoryIssue
+				if inSuggestionsSection {
+					category = CategorySuggestion
+				}
+				file, startLine, endLine := parseLocation(trimmed)
+				debugLog.Debug("parse: new item",
+					"line", lineNum,
+					"number", itemNum,
+					"category", category,
+					"file", file,
+					"start", startLine,
+					"end", endLine)
 				currentItem = &ReviewItem{
 					number:     itemNum,
 					title:      trimmed,
 					content:    "",
 					codeBlocks: []string{},
 					severity:   "Low",
+					category:   category,
+					file:       file,
+					startLine:  startLine,
+					endLine:    endLine,
+					patch:      currentPatch,
 				}
 				itemNum++
 				continue
 			}
+			if strings.Contains(trimmed, "📄") {
+				debugLog.Debug("parse: file marker without a known extension, kept as text", "line", lineNum, "text", trimmed)
+			}
 		}
 
 		if strings.HasPrefix(trimmed, "```") {
@@ -257,6 +297,7 @@ func parseReviewIntoItems(review string) []ReviewItem {
 			} else {
 				currentItem.severity = "Low"
 			}
+			debugLog.Debug("parse: severity", "line", lineNum, "number", currentItem.number, "severity", currentItem.severity)
 			continue
 		}
 
@@ -271,6 +312,7 @@ func parseReviewIntoItems(review string) []ReviewItem {
 	if currentItem != nil {
 		items = append(items, *currentItem)
 	}
+	debugLog.Debug("parse: done", "items", len(items), "lines", len(lines), "duration", time.Since(start))
 
 	return items
 }
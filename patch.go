@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// patch is one change read from stdin, a .patch or .diff file or an mbox
+// series. Patches written by git format-patch carry their commit message.
+type patch struct {
+	subject string
+	author  string
+	message string
+	diff    string
+}
+
+// patchExtensions are the files reviewed as patches rather than through
+// git diff
+var patchExtensions = []string{".patch", ".diff", ".mbox", ".eml"}
+
+// mboxSeparator starts a message in an mbox, such as the
+// "From 1a2b3c Mon Sep 17 00:00:00 2001" line written by git format-patch
+var mboxSeparator = regexp.MustCompile(`^From \S+ .*\d{4}$`)
+
+// patchPrefix matches the "[PATCH v2 3/7]" tag of a mailed subject
+var patchPrefix = regexp.MustCompile(`^\[[^\]]*\]\s*`)
+
+// patchHeading introduces each patch's review in the review of a series
+var patchHeading = regexp.MustCompile(`^## Patch (\d+)/(\d+): (.*)$`)
+
+// isPatchTarget reports whether a target is read as a patch: "-" for stdin
+// or a file with a patch extension
+func isPatchTarget(target string) bool {
+	if target == "-" {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(target))
+	for _, known := range patchExtensions {
+		if ext == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadPatches reads the patches from each target in order
+func loadPatches(targets []string, stdin io.Reader) ([]patch, error) {
+	patches := []patch{}
+	for _, target := range targets {
+		var (
+			data []byte
+			err  error
+		)
+		if target == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(target)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", target, err)
+		}
+
+		parsed := parsePatches(string(data))
+		debugLog.Debug("patches", "source", target, "bytes", len(data), "patches", len(parsed))
+		patches = append(patches, parsed...)
+	}
+
+	return patches, nil
+}
+
+// parsePatches splits an mbox or git format-patch output into patches. Input
+// without mail headers is a single plain diff. Messages without a diff, such
+// as a cover letter, are skipped.
+func parsePatches(data string) []patch {
+	lines := strings.SplitAfter(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || !mboxSeparator.MatchString(strings.TrimRight(lines[0], "\n")) {
+		if strings.TrimSpace(data) == "" {
+			return nil
+		}
+		return []patch{{diff: data}}
+	}
+
+	patches := []patch{}
+	var message []string
+	flush := func() {
+		if p, ok := parseMessage(message); ok {
+			patches = append(patches, p)
+		}
+		message = nil
+	}
+	for _, line := range lines {
+		if mboxSeparator.MatchString(strings.TrimRight(line, "\n")) {
+			flush()
+			continue
+		}
+		message = append(message, line)
+	}
+	flush()
+
+	return patches
+}
+
+// parseMessage reads the headers, commit message and diff of one mailed
+// patch
+func parseMessage(lines []string) (patch, bool) {
+	var p patch
+	headers := map[string]string{}
+	last := ""
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\n")
+		if line == "" {
+			i++
+			break
+		}
+		// Folded header lines continue the previous header
+		if (line[0] == ' ' || line[0] == '\t') && last != "" {
+			headers[last] += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		last = strings.ToLower(name)
+		headers[last] = strings.TrimSpace(value)
+	}
+
+	decoder := new(mime.WordDecoder)
+	decode := func(value string) string {
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			return decoded
+		}
+		return value
+	}
+	p.subject = patchPrefix.ReplaceAllString(decode(headers["subject"]), "")
+	p.author = decode(headers["from"])
+
+	// The commit message ends at the "---" before the diffstat, or at the
+	// diff itself
+	var message, diff []string
+	inDiff := false
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if !inDiff && strings.HasPrefix(line, ">From ") {
+			// Unescape mboxrd quoting
+			line = line[1:]
+		}
+		if !inDiff && (strings.HasPrefix(line, "diff --git ") || strings.HasPrefix(line, "Index: ")) {
+			inDiff = true
+		}
+		if inDiff {
+			diff = append(diff, line)
+			continue
+		}
+		if strings.TrimRight(line, "\n") == "---" {
+			// Skip the diffstat up to the diff
+			for i+1 < len(lines) && !strings.HasPrefix(lines[i+1], "diff --git ") && !strings.HasPrefix(lines[i+1], "Index: ") {
+				i++
+			}
+			continue
+		}
+		message = append(message, line)
+	}
+
+	// git format-patch signs off with "-- " and the git version. Diff lines
+	// never follow the signature, and without a version line a final "-- " is
+	// a removed "- " line.
+	signed := false
+	for j := len(diff) - 1; j >= 0; j-- {
+		line := strings.TrimRight(diff[j], "\n")
+		if line == "-- " && signed {
+			diff = diff[:j]
+			break
+		}
+		if line != "" && strings.ContainsAny(line[:1], " +-@\\") {
+			break
+		}
+		signed = signed || line != ""
+	}
+
+	p.message = strings.TrimSpace(strings.Join(message, ""))
+	p.diff = strings.Join(diff, "")
+	if strings.TrimSpace(p.diff) == "" {
+		debugLog.Debug("patches: skipped message without a diff", "subject", p.subject)
+		return p, false
+	}
+
+	return p, true
+}
+
+// patchTitle names a patch in headings and group headers
+func patchTitle(p patch, index, total int) string {
+	subject := p.subject
+	if subject == "" {
+		subject = "(no subject)"
+	}
+
+	return fmt.Sprintf("%d/%d: %s", index+1, total, subject)
+}
+
+// patchDiff returns the diff a finding refers to. In a series that is the
+// diff of the patch it was made on, since two patches can change the same
+// lines; otherwise it is the whole diff.
+func patchDiff(diff string, patches []patch, item ReviewItem) string {
+	number, _, _ := strings.Cut(item.patch, "/")
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > len(patches) {
+		return diff
+	}
+
+	return patches[n-1].diff
+}
+
+// joinDiffs returns the combined diff of a series, used for session keys and
+// for findings that name no patch
+func joinDiffs(patches []patch) string {
+	var b strings.Builder
+	for _, p := range patches {
+		b.WriteString(p.diff)
+		if !strings.HasSuffix(p.diff, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// seriesMessage joins the subjects and commit messages of a series, giving
+// follow-up questions the context of the whole series
+func seriesMessage(patches []patch) string {
+	var parts []string
+	for i, p := range patches {
+		if p.subject == "" && p.message == "" {
+			continue
+		}
+		parts = append(parts, strings.TrimSpace(patchTitle(p, i, len(patches))+"\n\n"+p.message))
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// preparePatches applies the ignore rules and redaction to each patch and
+// drops the patches left without changes. The returned preparedDiff covers
+// the whole series.
+func preparePatches(patches []patch, ignore []string, redact bool) ([]patch, preparedDiff) {
+	kept := []patch{}
+	total := preparedDiff{redacted: map[string]int{}}
+	for _, p := range patches {
+		prepared := prepareDiff(p.diff, ignore, redact)
+		total.included = append(total.included, prepared.included...)
+		total.excluded = append(total.excluded, prepared.excluded...)
+		for file, n := range prepared.redacted {
+			total.redacted[file] += n
+		}
+		if strings.TrimSpace(prepared.diff) == "" {
+			continue
+		}
+		p.diff = prepared.diff
+		kept = append(kept, p)
+	}
+	total.diff = joinDiffs(kept)
+
+	return kept, total
+}
+
+// reviewSeries reviews each patch on its own, with its commit message, and
+// joins the reviews under one heading per patch. A single patch is reviewed
+// without a heading.
+func reviewSeries(apiKey string, opts reviewOptions, patches []patch) (string, Usage, error) {
+	if len(patches) == 1 {
+		return reviewPatch(apiKey, opts, patches[0])
+	}
+
+	var (
+		b     strings.Builder
+		total Usage
+	)
+	for i, p := range patches {
+		review, usage, err := reviewPatch(apiKey, opts, p)
+		if err != nil {
+			return "", total, fmt.Errorf("patch %s: %v", patchTitle(p, i, len(patches)), err)
+		}
+		total.PromptTokens += usage.PromptTokens
+		total.CompletionTokens += usage.CompletionTokens
+		total.TotalTokens += usage.TotalTokens
+
+		fmt.Fprintf(&b, "## Patch %s\n\n%s\n\n", patchTitle(p, i, len(patches)), strings.TrimSpace(review))
+	}
+
+	return b.String(), total, nil
+}
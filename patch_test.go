@@ -0,0 +1,211 @@
+package main
+
+import "testing"
+
+// testSeries is git format-patch output with a cover letter and two patches
+const testSeries = `From 0000000000000000000000000000000000000000 Mon Sep 17 00:00:00 2001
+From: A U Thor <author@example.com>
+Date: Sat, 17 Oct 2026 10:00:00 +0000
+Subject: [PATCH 0/2] *** SUBJECT HERE ***
+
+*** BLURB HERE ***
+
+A U Thor (2):
+  Add b to a
+  Add x.go
+
+ a.txt | 1 +
+ x.go  | 1 +
+ 2 files changed, 2 insertions(+)
+
+-- 
+2.39.5
+
+From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001
+From: =?UTF-8?q?Ren=C3=A9=20Author?= <rene@example.com>
+Date: Sat, 17 Oct 2026 10:00:00 +0000
+Subject: [PATCH 1/2] Add b to a with a subject that is long
+ enough to be folded
+
+Explains why b is needed.
+>From here on the message is quoted.
+
+---
+ a.txt | 1 +
+ 1 file changed, 1 insertion(+)
+
+diff --git a/a.txt b/a.txt
+index 7898192..422c2b7 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1 +1,2 @@
+ a
++b
+-- 
+2.39.5
+
+From 2222222222222222222222222222222222222222 Mon Sep 17 00:00:00 2001
+From: A U Thor <author@example.com>
+Date: Sat, 17 Oct 2026 10:00:00 +0000
+Subject: [PATCH 2/2] Add x.go
+
+---
+ x.go | 1 +
+ 1 file changed, 1 insertion(+)
+ create mode 100644 x.go
+
+diff --git a/x.go b/x.go
+new file mode 100644
+index 0000000..823aafd
+--- /dev/null
++++ b/x.go
+@@ -0,0 +1 @@
++package x
+-- 
+2.39.5
+`
+
+func TestParsePatchesSeries(t *testing.T) {
+	patches := parsePatches(testSeries)
+	if len(patches) != 2 {
+		t.Fatalf("parsePatches returned %d patches, want 2", len(patches))
+	}
+
+	tests := []struct {
+		subject, author, message, diff string
+	}{
+		{
+			subject: "Add b to a with a subject that is long enough to be folded",
+			author:  "René Author <rene@example.com>",
+			message: "Explains why b is needed.\nFrom here on the message is quoted.",
+			diff:    "diff --git a/a.txt b/a.txt\nindex 7898192..422c2b7 100644\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1,2 @@\n a\n+b\n",
+		},
+		{
+			subject: "Add x.go",
+			author:  "A U Thor <author@example.com>",
+			diff:    "diff --git a/x.go b/x.go\nnew file mode 100644\nindex 0000000..823aafd\n--- /dev/null\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		},
+	}
+	for i, tt := range tests {
+		p := patches[i]
+		if p.subject != tt.subject || p.author != tt.author || p.message != tt.message || p.diff != tt.diff {
+			t.Errorf("patch %d = %+v\nwant subject %q, author %q, message %q, diff %q", i+1, p, tt.subject, tt.author, tt.message, tt.diff)
+		}
+	}
+
+	if got, want := patchTitle(patches[1], 1, len(patches)), "2/2: Add x.go"; got != want {
+		t.Errorf("patchTitle = %q, want %q", got, want)
+	}
+	if got := joinDiffs(patches); got != tests[0].diff+tests[1].diff {
+		t.Errorf("joinDiffs =\n%s", got)
+	}
+}
+
+func TestParsePatchesPlainDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"git diff", "diff --git a/a b/a\n--- a/a\n+++ b/a\n@@ -1 +1 @@\n-x\n+y\n", 1},
+		{"unified diff", "--- a.txt\t2026-10-17\n+++ b.txt\t2026-10-18\n@@ -1 +1 @@\n-x\n+y\n", 1},
+		{"crlf", "diff --git a/a b/a\r\n+y\r\n", 1},
+		{"empty", "", 0},
+		{"blank", "\n  \n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patches := parsePatches(tt.in)
+			if len(patches) != tt.want {
+				t.Fatalf("parsePatches returned %d patches, want %d", len(patches), tt.want)
+			}
+			if tt.want == 1 {
+				p := patches[0]
+				if p.subject != "" || p.message != "" || p.diff != tt.in {
+					t.Errorf("plain diff parsed as %+v", p)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePatchesSignature(t *testing.T) {
+	header := "From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001\n" +
+		"Subject: [PATCH] Drop a list item\n" +
+		"\n"
+	// The last line removes "- second" from a Markdown list
+	diff := "diff --git a/list.md b/list.md\n" +
+		"--- a/list.md\n" +
+		"+++ b/list.md\n" +
+		"@@ -1,2 +1 @@\n" +
+		" - first\n" +
+		"-- \n"
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"signed", header + diff + "-- \n2.39.5\n\n"},
+		{"without a signature", header + diff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patches := parsePatches(tt.in)
+			if len(patches) != 1 {
+				t.Fatalf("parsePatches returned %d patches, want 1", len(patches))
+			}
+			if patches[0].diff != diff {
+				t.Errorf("diff = %q, want %q", patches[0].diff, diff)
+			}
+			if patches[0].subject != "Drop a list item" {
+				t.Errorf("subject = %q", patches[0].subject)
+			}
+		})
+	}
+}
+
+func TestIsPatchTarget(t *testing.T) {
+	tests := map[string]bool{
+		"-":              true,
+		"fix.patch":      true,
+		"out/0001.PATCH": true,
+		"change.diff":    true,
+		"series.mbox":    true,
+		"mail.eml":       true,
+		".":              false,
+		"main.go":        false,
+		"patch":          false,
+	}
+
+	for target, want := range tests {
+		if got := isPatchTarget(target); got != want {
+			t.Errorf("isPatchTarget(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestPatchDiff(t *testing.T) {
+	first := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1,2 @@\n a\n+b\n"
+	second := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"
+	patches := []patch{{subject: "Add b", diff: first}, {subject: "Replace b", diff: second}}
+	diff := joinDiffs(patches)
+
+	tests := []struct {
+		patch string
+		want  string
+	}{
+		{"1/2: Add b", "@@ -1 +1,2 @@\n a\n+b"},
+		{"2/2: Replace b", "@@ -1,2 +1,2 @@\n a\n-b\n+c"},
+		{"", "@@ -1 +1,2 @@\n a\n+b"},
+		{"3/2: Out of range", "@@ -1 +1,2 @@\n a\n+b"},
+	}
+
+	for _, tt := range tests {
+		item := ReviewItem{file: "a.txt", startLine: 2, patch: tt.patch}
+		if got := diffHunk(patchDiff(diff, patches, item), item.file, item.startLine); got != tt.want {
+			t.Errorf("hunk for patch %q =\n%s\nwant\n%s", tt.patch, got, tt.want)
+		}
+	}
+}
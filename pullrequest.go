@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultGitHubAPI is used unless --api-url, $GITHUB_API_URL or the config
+// point at a GitHub Enterprise server
+const defaultGitHubAPI = "https://api.github.com"
+
+// Hidden markers that let later runs find the comments revyu posted
+const (
+	summaryMarker     = "<!-- revyu:summary -->"
+	fingerprintMarker = "<!-- revyu:%s -->"
+)
+
+var fingerprintPattern = regexp.MustCompile(`<!-- revyu:([0-9a-f]{16}) -->`)
+
+// githubClient talks to the GitHub REST API for one repository
+type githubClient struct {
+	baseURL string
+	token   string
+	repo    string
+	http    *http.Client
+}
+
+type ghPull struct {
+	Head struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+type ghComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+type ghReview struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+type ghReviewComment struct {
+	Path      string `json:"path"`
+	Body      string `json:"body"`
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+	StartLine int    `json:"start_line,omitempty"`
+	StartSide string `json:"start_side,omitempty"`
+}
+
+type ghNewReview struct {
+	CommitID string            `json:"commit_id"`
+	Body     string            `json:"body"`
+	Event    string            `json:"event"`
+	Comments []ghReviewComment `json:"comments"`
+}
+
+// request sends one API request and returns the response body. Non-2xx
+// responses are turned into errors that include GitHub's message.
+func (c githubClient) request(method, path, accept string, body any) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(c.baseURL, "/")+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if accept == "" {
+		accept = "application/vnd.github+json"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub API call failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return nil, fmt.Errorf("GitHub API %s %s: %s: %s", method, path, resp.Status, apiErr.Message)
+	}
+
+	return data, nil
+}
+
+// getJSON fetches a path and decodes the JSON response into out
+func (c githubClient) getJSON(path string, out any) error {
+	data, err := c.request("GET", path, "", nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	return nil
+}
+
+// jsonGetter is an API client that can fetch and decode a path
+type jsonGetter interface {
+	getJSON(path string, out any) error
+}
+
+// listAll fetches every page of a list endpoint. GitHub and GitLab both page
+// with per_page and page.
+func listAll[T any](c jsonGetter, path string) ([]T, error) {
+	all := []T{}
+	for page := 1; ; page++ {
+		var items []T
+		if err := c.getJSON(fmt.Sprintf("%s?per_page=100&page=%d", path, page), &items); err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < 100 {
+			return all, nil
+		}
+	}
+}
+
+// findingBody renders a finding as a review comment. Suggested code goes in a
+// plain code block: the model does not say which lines it replaces, so it is
+// not offered as a change the code host can apply.
+func findingBody(item ReviewItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s %s** (revyu #%d)\n\n", item.severity, strings.ToLower(string(item.category)), item.number)
+	b.WriteString(item.content)
+	b.WriteString("\n")
+
+	if code := suggestionText(item); code != "" && item.category == CategorySuggestion {
+		fmt.Fprintf(&b, "\n```\n%s\n```\n", code)
+	}
+
+	fmt.Fprintf(&b, "\n"+fingerprintMarker+"\n", itemFingerprint(item))
+	return b.String()
+}
+
+// githubOptions holds the flags of `revyu github review`
+type githubOptions struct {
+	pr     int
+	repo   string
+	apiURL string
+
+	// settings holds the config values given as flags, keyed by setting
+	settings map[string]string
+}
+
+// githubFlags defines the flags of `revyu github review`
+func githubFlags(opts *githubOptions) *flag.FlagSet {
+	fs := newFlagSet("github review")
+	fs.IntVar(&opts.pr, "pr", 0, "pull request `number`")
+	fs.StringVar(&opts.repo, "repo", "", "repository as `owner/name`")
+	fs.StringVar(&opts.apiURL, "api-url", "", "GitHub API base `URL`")
+	debugFlags(fs)
+	return fs
+}
+
+func parseGitHubArgs(args []string) (githubOptions, error) {
+	var opts githubOptions
+
+	fs := githubFlags(&opts)
+	if err := parseFlags(fs, args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if opts.pr <= 0 {
+		return opts, fmt.Errorf("--pr is required")
+	}
+
+	if opts.repo == "" {
+		opts.repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if opts.repo == "" {
+		if url, err := gitRemoteURL("origin"); err == nil {
+			opts.repo = remoteProjectPath(url)
+		}
+	}
+	if strings.Count(opts.repo, "/") != 1 {
+		return opts, fmt.Errorf("could not determine the repository, pass --repo owner/name")
+	}
+	opts.settings = flagSettings(fs)
+
+	return opts, nil
+}
+
+// printGitHubUsage prints the help of `revyu github review`
+func printGitHubUsage() {
+	printHelp("github review --pr N [--repo owner/name] [--api-url URL]",
+		"Reviews a pull request and posts the findings as a pull request review.\n"+
+			"The token is read from $GITHUB_TOKEN or $GH_TOKEN.",
+		githubFlags(&githubOptions{}))
+}
+
+// runGitHub runs `revyu github <command>` and returns the exit code
+func runGitHub(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		printGitHubUsage()
+		return exitOK
+	}
+	if len(args) == 0 || args[0] != "review" {
+		printGitHubUsage()
+		return exitUsage
+	}
+
+	opts, err := parseGitHubArgs(args[1:])
+	if err != nil {
+		return usageError(err, printGitHubUsage)
+	}
+
+	cfg, _, _, ok := loadSettings(opts.settings)
+	if !ok {
+		return exitError
+	}
+	for _, url := range []string{opts.apiURL, os.Getenv("GITHUB_API_URL"), cfg.GitHubAPI, defaultGitHubAPI} {
+		if url != "" {
+			opts.apiURL = url
+			break
+		}
+	}
+
+	closeLog, err := startLogging(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return exitError
+	}
+	defer closeLog()
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "Error: set GITHUB_TOKEN or GH_TOKEN to post reviews")
+		return exitError
+	}
+	addLogSecret(token)
+
+	apiKey, ok := requireAPIKey(cfg)
+	if !ok {
+		return exitError
+	}
+
+	client := githubClient{
+		baseURL: opts.apiURL,
+		token:   token,
+		repo:    opts.repo,
+		http:    &http.Client{Timeout: 60 * time.Second},
+	}
+
+	summary, err := reviewPullRequest(client, apiKey, opts.pr, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error reviewing pull request: "+err.Error())
+		return exitError
+	}
+
+	fmt.Println(summary)
+	return exitOK
+}
+
+// reviewPullRequest reviews a pull request and posts the findings. Comments
+// from earlier runs are matched by fingerprint and edited in place, and the
+// summary review is updated when there is nothing new to post.
+func reviewPullRequest(c githubClient, apiKey string, number int, cfg Config) (string, error) {
+	pullPath := fmt.Sprintf("/repos/%s/pulls/%d", c.repo, number)
+
+	var pull ghPull
+	if err := c.getJSON(pullPath, &pull); err != nil {
+		return "", err
+	}
+
+	raw, err := c.request("GET", pullPath, "application/vnd.github.v3.diff", nil)
+	if err != nil {
+		return "", err
+	}
+	diff := prepareDiff(string(raw), cfg.Ignore, cfg.Redact).diff
+	if strings.TrimSpace(diff) == "" {
+		return "Pull request has no changes", nil
+	}
+
+	run, err := runReview(apiKey, fmt.Sprintf("%s#%d", c.repo, number), diff, cfg.reviewOptions())
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := listAll[ghComment](c, pullPath+"/comments")
+	if err != nil {
+		return "", err
+	}
+	posted := map[string]int64{}
+	for _, comment := range existing {
+		if match := fingerprintPattern.FindStringSubmatch(comment.Body); match != nil {
+			posted[match[1]] = comment.ID
+		}
+	}
+
+	files := parseDiffLines(diff)
+	var comments []ghReviewComment
+	var outside []ReviewItem
+	updated := 0
+	for _, item := range run.items {
+		if item.dismissed {
+			continue
+		}
+
+		start, end, ok := commentRange(files, item)
+		if id, found := posted[itemFingerprint(item)]; found {
+			body := map[string]string{"body": findingBody(item)}
+			if _, err := c.request("PATCH", fmt.Sprintf("/repos/%s/pulls/comments/%d", c.repo, id), "", body); err != nil {
+				return "", err
+			}
+			updated++
+			continue
+		}
+		if !ok {
+			outside = append(outside, item)
+			continue
+		}
+
+		comment := ghReviewComment{Path: item.file, Body: findingBody(item), Line: end, Side: "RIGHT"}
+		if start != end {
+			comment.StartLine = start
+			comment.StartSide = "RIGHT"
+		}
+		comments = append(comments, comment)
+	}
+
+	body := summaryBody(run, outside)
+
+	reviews, err := listAll[ghReview](c, pullPath+"/reviews")
+	if err != nil {
+		return "", err
+	}
+	var previous int64
+	for _, review := range reviews {
+		if strings.Contains(review.Body, summaryMarker) {
+			previous = review.ID
+		}
+	}
+
+	if previous == 0 {
+		review := ghNewReview{CommitID: pull.Head.SHA, Body: body, Event: "COMMENT", Comments: comments}
+		if _, err := c.request("POST", pullPath+"/reviews", "", review); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Posted a review with %d new comments and updated %d on %s#%d", len(comments), updated, c.repo, number), nil
+	}
+
+	// The summary lives in the first revyu review and is edited in place, so
+	// new findings are posted in a review of their own without another copy
+	if _, err := c.request("PUT", fmt.Sprintf("%s/reviews/%d", pullPath, previous), "", map[string]string{"body": body}); err != nil {
+		return "", err
+	}
+	if len(comments) == 0 {
+		return fmt.Sprintf("Updated the revyu review and %d comments on %s#%d", updated, c.repo, number), nil
+	}
+
+	review := ghNewReview{CommitID: pull.Head.SHA, Body: newFindingsBody(len(comments)), Event: "COMMENT", Comments: comments}
+	if _, err := c.request("POST", pullPath+"/reviews", "", review); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Updated the revyu review, posted %d new comments and updated %d on %s#%d", len(comments), updated, c.repo, number), nil
+}
+
+// newFindingsBody is the short body of a review that only adds comments.
+// GitHub requires a body for COMMENT reviews.
+func newFindingsBody(count int) string {
+	if count == 1 {
+		return "revyu found 1 new issue since the last review. The summary above is kept up to date."
+	}
+
+	return fmt.Sprintf("revyu found %d new issues since the last review. The summary above is kept up to date.", count)
+}
+
+// summaryBody renders the summary posted on a pull or merge request, with the
+// findings that could not be attached to a line of the diff
+func summaryBody(run reviewRun, outside []ReviewItem) string {
+	body := summaryMarker + "\n" + stepSummary(run)
+	if len(outside) == 0 {
+		return body
+	}
+
+	body += "\n### Outside the diff\n\n"
+	for _, item := range outside {
+		location := item.file
+		if item.startLine > 0 {
+			location = fmt.Sprintf("%s:%d", item.file, item.startLine)
+		}
+		body += fmt.Sprintf("- **%s** `%s` %s\n", item.severity, location, strings.ReplaceAll(item.content, "\n", " "))
+	}
+
+	return body
+}
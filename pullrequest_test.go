@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindingBodyUsesPlainFence(t *testing.T) {
+	item := ReviewItem{number: 2, severity: SeverityLow, category: CategorySuggestion, content: "Use errors.Is", codeBlocks: []string{"errors.Is(err, fs.ErrNotExist)"}}
+	body := findingBody(item)
+
+	if strings.Contains(body, "```suggestion") {
+		t.Errorf("findingBody offers an applicable suggestion:\n%s", body)
+	}
+	if !strings.Contains(body, "\n```\nerrors.Is(err, fs.ErrNotExist)\n```\n") {
+		t.Errorf("findingBody does not show the code:\n%s", body)
+	}
+	if !strings.Contains(body, itemFingerprint(item)) {
+		t.Errorf("findingBody has no fingerprint:\n%s", body)
+	}
+}
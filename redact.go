@@ -0,0 +1,100 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// redactedMarker replaces a secret found in the diff before it is sent
+const redactedMarker = "[REDACTED]"
+
+// secretPatterns match well-known credential formats. They are redacted from
+// diffs before review and masked in the debug log. Each token pattern starts
+// at a word boundary so identifiers such as risk-assessment-module are left
+// alone.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// redactSecrets replaces everything that looks like a credential and
+// returns how many were found. Line breaks inside a match are kept, with the
+// diff marker of each line, so hunk line numbers stay valid.
+func redactSecrets(text string) (string, int) {
+	count := 0
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			count++
+			var b strings.Builder
+			b.WriteString(redactedMarker)
+			for i := 0; i < len(match); i++ {
+				if match[i] != '\n' {
+					continue
+				}
+				b.WriteByte('\n')
+				if i+1 < len(match) && strings.ContainsRune("+- ", rune(match[i+1])) {
+					b.WriteByte(match[i+1])
+				}
+			}
+			return b.String()
+		})
+	}
+
+	return text, count
+}
+
+// preparedDiff is a diff as it will be sent for review, with a record of
+// what was left out
+type preparedDiff struct {
+	diff     string
+	included []string
+	excluded []string
+	redacted map[string]int
+}
+
+// prepareDiff drops the ignored files from a diff and, with redact set,
+// redacts secrets file by file. What is left is recorded in the debug log.
+func prepareDiff(diff string, ignore []string, redact bool) preparedDiff {
+	start := time.Now()
+	filtered, included, excluded := filterDiff(diff, ignore)
+	prepared := preparedDiff{diff: filtered, included: included, excluded: excluded, redacted: map[string]int{}}
+
+	if redact {
+		var b, section strings.Builder
+		file := ""
+		flush := func() {
+			text, count := redactSecrets(section.String())
+			if count > 0 {
+				prepared.redacted[file] += count
+			}
+			b.WriteString(text)
+			section.Reset()
+		}
+		for _, line := range strings.SplitAfter(filtered, "\n") {
+			if strings.HasPrefix(line, "diff --git ") {
+				flush()
+				file = diffSectionPath(strings.TrimSpace(line))
+			}
+			section.WriteString(line)
+		}
+		flush()
+		prepared.diff = b.String()
+	}
+
+	debugLog.Debug("diff",
+		"bytes", len(diff),
+		"prepared_bytes", len(prepared.diff),
+		"lines", strings.Count(prepared.diff, "\n"),
+		"included", prepared.included,
+		"excluded", prepared.excluded,
+		"redacted", prepared.redacted,
+		"duration", time.Since(start))
+
+	return prepared
+}
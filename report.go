@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// reportVersion is bumped whenever a field of Report changes meaning or is removed
+const reportVersion = 1
+
+// reviewRun is the outcome of a non-interactive review, shared by every
+// output format
+type reviewRun struct {
+	target   string
+	base     string
+	head     string
+	diff     string
+	patches  []patch
+	opts     reviewOptions
+	review   string
+	usage    Usage
+	started  time.Time
+	duration time.Duration
+	items    []ReviewItem
+}
+
+// diffRevisions returns the base and head a review of target compares. Plain
+// git diff compares the working tree against the index, and --staged the
+// index against the HEAD commit. Diffs read from stdin have neither, and
+// patch files are named as the head.
+func diffRevisions(target string, staged, patches bool) (base, head string) {
+	switch {
+	case target == "-":
+		return "", ""
+	case patches:
+		return "", target
+	case !staged:
+		return "index", "worktree"
+	}
+
+	base, _ = gitRevParse("HEAD")
+	return base, "index"
+}
+
+// runReview reviews the diff synchronously and marks findings that were
+// dismissed in earlier runs. Outside a git checkout there are no suppressions.
+func runReview(apiKey, target, diff string, opts reviewOptions) (reviewRun, error) {
+	return runSeriesReview(apiKey, target, diff, nil, opts)
+}
+
+// runSeriesReview is runReview for a patch series, reviewing each patch on
+// its own. Without patches the diff is reviewed as a whole.
+func runSeriesReview(apiKey, target, diff string, patches []patch, opts reviewOptions) (reviewRun, error) {
+	run := reviewRun{target: target, diff: diff, patches: patches, opts: opts, started: time.Now()}
+
+	var (
+		review string
+		usage  Usage
+		err    error
+	)
+	if patches != nil {
+		review, usage, err = reviewSeries(apiKey, opts, patches)
+	} else {
+		review, usage, err = reviewDiff(apiKey, opts, diff)
+	}
+	run.duration = time.Since(run.started)
+	if err != nil {
+		return run, err
+	}
+	run.review = review
+	run.usage = usage
+	run.items = parseReviewIntoItems(review)
+
+	if _, err := gitToplevel(); err != nil {
+		return run, nil
+	}
+	suppressions, err := loadSuppressions()
+	if err != nil {
+		return run, err
+	}
+	for i := range run.items {
+		run.items[i].dismissed = isSuppressed(run.items[i], suppressions)
+	}
+
+	return run, nil
+}
+
+// Report is the JSON document printed by --format json
+type Report struct {
+	Version  int       `json:"version"`
+	Tool     string    `json:"tool"`
+	Run      RunInfo   `json:"run"`
+	Summary  string    `json:"summary"`
+	Findings []Finding `json:"findings"`
+}
+
+// RunInfo describes what was reviewed and how
+type RunInfo struct {
+	Target     string    `json:"target"`
+	Base       string    `json:"base"`
+	Head       string    `json:"head"`
+	DiffHash   string    `json:"diff_hash"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	Profile    string    `json:"profile"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Usage      Usage     `json:"usage"`
+}
+
+// Finding is one review item in the JSON report
+type Finding struct {
+	Number      int      `json:"number"`
+	Title       string   `json:"title"`
+	File        string   `json:"file,omitempty"`
+	StartLine   int      `json:"start_line,omitempty"`
+	EndLine     int      `json:"end_line,omitempty"`
+	Patch       string   `json:"patch,omitempty"`
+	Severity    string   `json:"severity"`
+	Category    string   `json:"category"`
+	Content     string   `json:"content"`
+	CodeBlocks  []string `json:"code_blocks"`
+	Fingerprint string   `json:"fingerprint"`
+	Suppressed  bool     `json:"suppressed"`
+}
+
+// newReport builds the JSON document for a finished run
+func newReport(run reviewRun) Report {
+	report := Report{
+		Version: reportVersion,
+		Tool:    "revyu",
+		Run: RunInfo{
+			Target:     run.target,
+			Base:       run.base,
+			Head:       run.head,
+			DiffHash:   diffHash(run.diff),
+			Provider:   run.opts.Provider,
+			Model:      run.opts.Model,
+			Profile:    run.opts.Profile,
+			StartedAt:  run.started.UTC(),
+			DurationMs: run.duration.Milliseconds(),
+			Usage:      run.usage,
+		},
+		Summary:  extractSection(run.review, "Summary"),
+		Findings: []Finding{},
+	}
+
+	for _, item := range run.items {
+		codeBlocks := item.codeBlocks
+		if codeBlocks == nil {
+			codeBlocks = []string{}
+		}
+		report.Findings = append(report.Findings, Finding{
+			Number:      item.number,
+			Title:       strings.TrimSpace(strings.ReplaceAll(item.title, "📄", "")),
+			File:        item.file,
+			StartLine:   item.startLine,
+			EndLine:     item.endLine,
+			Patch:       item.patch,
+			Severity:    string(item.severity),
+			Category:    string(item.category),
+			Content:     item.content,
+			CodeBlocks:  codeBlocks,
+			Fingerprint: itemFingerprint(item),
+			Suppressed:  item.dismissed,
+		})
+	}
+
+	return report
+}
+
+// jsonReport renders the run as an indented JSON document
+func jsonReport(run reviewRun) (string, error) {
+	data, err := json.MarshalIndent(newReport(run), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	root := initTestRepo(t)
+	commit := exec.Command("git", "-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+	commit.Dir = root
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, out)
+	}
+	head, err := gitRevParse("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		staged     bool
+		patches    bool
+		base, want string
+	}{
+		{"working tree", ".", false, false, "index", "worktree"},
+		{"staged", ".", true, false, head, "index"},
+		{"stdin", "-", false, true, "", ""},
+		{"patch files", "a.patch b.patch", false, true, "", "a.patch b.patch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, got := diffRevisions(tt.target, tt.staged, tt.patches)
+			if base != tt.base || got != tt.want {
+				t.Errorf("diffRevisions = %q, %q; want %q, %q", base, got, tt.base, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rerunForm is the small form shown before re-running the review
+type rerunForm struct {
+	fields []string
+	values []string
+	focus  int
+	err    error
+}
+
+func newRerunForm(target string, opts reviewOptions) *rerunForm {
+	return &rerunForm{
+		fields: []string{"Target", "Provider", "Model", "Profile"},
+		values: []string{target, opts.Provider, opts.Model, opts.Profile},
+	}
+}
+
+// options reads the review options back from the form
+func (f rerunForm) options() (string, reviewOptions) {
+	return strings.TrimSpace(f.values[0]), reviewOptions{
+		Provider: strings.TrimSpace(f.values[1]),
+		Model:    strings.TrimSpace(f.values[2]),
+		Profile:  strings.TrimSpace(f.values[3]),
+	}
+}
+
+// runDelta is a finding of the previous run together with whether the new
+// review still reports it
+type runDelta struct {
+	item     SavedItem
+	resolved bool
+}
+
+// compareRuns matches the previous run's findings against the new items,
+// carrying the checked state over to findings that are still reported
+func compareRuns(items []ReviewItem, previous []SavedItem) []runDelta {
+	deltas := []runDelta{}
+	claimed := map[int]bool{}
+	for _, saved := range previous {
+		idx := matchSaved(items, saved, claimed)
+		if idx >= 0 {
+			claimed[idx] = true
+			items[idx].checked = items[idx].checked || saved.Checked
+		}
+		deltas = append(deltas, runDelta{item: saved, resolved: idx < 0})
+	}
+
+	return deltas
+}
+
+// rerun fetches a fresh diff for the target and reviews it with the new
+// options. A patch series is reviewed again as it was loaded unless the
+// target was changed.
+func (m model) rerun(target string, opts reviewOptions) tea.Cmd {
+	return func() tea.Msg {
+		if m.patches != nil && target == m.filePath {
+			// Patches do not change on disk between runs
+			review, _, err := reviewSeries(m.apiKey, opts, m.patches)
+			return reviewMsg{review: review, err: err, target: target, diff: m.diff, rerun: true}
+		}
+
+		diff, err := getGitDiff(target, m.staged)
+		if err != nil {
+			return reviewMsg{err: err, target: target, diff: diff, rerun: true}
+		}
+		diff = prepareDiff(diff, m.ignore, m.redact).diff
+		if strings.TrimSpace(diff) == "" {
+			return reviewMsg{err: fmt.Errorf("no changes detected in git diff for %s", target), target: target, diff: diff, rerun: true}
+		}
+
+		review, _, err := reviewDiff(m.apiKey, opts, diff)
+		return reviewMsg{review: review, err: err, target: target, diff: diff, rerun: true}
+	}
+}
+
+// updateRerun edits the re-review form
+func (m model) updateRerun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.rerunForm
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		cmd := m.quit()
+		return m, cmd
+	case key.Matches(msg, m.keys.Back):
+		m.rerunForm = nil
+	case msg.Type == tea.KeyTab || msg.Type == tea.KeyDown:
+		f.focus = (f.focus + 1) % len(f.fields)
+	case msg.Type == tea.KeyShiftTab || msg.Type == tea.KeyUp:
+		f.focus = (f.focus + len(f.fields) - 1) % len(f.fields)
+	case msg.Type == tea.KeyEnter:
+		target, opts := f.options()
+		if target == "" {
+			f.err = fmt.Errorf("target must not be empty")
+			return m, nil
+		}
+		if err := opts.validate(); err != nil {
+			f.err = err
+			return m, nil
+		}
+
+		m.previousRun = saveItems(m.activeItems())
+		m.rerunForm = nil
+		m.opts = opts
+		m.loading = true
+		return m, m.rerun(target, opts)
+	case msg.Type == tea.KeyBackspace:
+		runes := []rune(f.values[f.focus])
+		if len(runes) > 0 {
+			f.values[f.focus] = string(runes[:len(runes)-1])
+		}
+	case msg.Type == tea.KeySpace:
+		f.values[f.focus] += " "
+	case msg.Type == tea.KeyRunes:
+		f.values[f.focus] += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// renderRerun draws the re-review form
+func (m model) renderRerun(s *strings.Builder) {
+	f := m.rerunForm
+	s.WriteString(theme.Subtitle.Render("Re-run the review (Tab: next field, Enter: run, Esc: cancel)"))
+	s.WriteString("\n")
+
+	for i, field := range f.fields {
+		line := fmt.Sprintf("%-9s %s", field+":", f.values[i])
+		style := theme.Normal
+		if i == f.focus {
+			line += "█"
+			style = style.Inherit(theme.Selected)
+		}
+		s.WriteString(theme.Content.Render(style.Render(line)))
+		s.WriteString("\n")
+	}
+
+	s.WriteString(theme.Subtitle.Render(fmt.Sprintf("Providers: %s  •  Profiles: %s",
+		strings.Join(supportedProviders, ", "), strings.Join(profileNames(), ", "))))
+	s.WriteString("\n")
+
+	if f.err != nil {
+		s.WriteString(theme.Error.Render(f.err.Error()))
+		s.WriteString("\n")
+	}
+}
+
+// renderComparison lists the previous run's findings and whether the new
+// review still reports them
+func (m model) renderComparison(s *strings.Builder) {
+	if len(m.comparison) == 0 {
+		return
+	}
+
+	resolved := 0
+	for _, d := range m.comparison {
+		if d.resolved {
+			resolved++
+		}
+	}
+
+	s.WriteString(theme.Separator.Render(strings.Repeat("─", m.maxWidth())))
+	s.WriteString("\n")
+	s.WriteString(theme.Group.Render(fmt.Sprintf("Previous run: %d resolved, %d still reported", resolved, len(m.comparison)-resolved)))
+	s.WriteString("\n\n")
+
+	for _, d := range m.comparison {
+		status := theme.Success.UnsetMargins().Render("✓ resolved")
+		if !d.resolved {
+			status = theme.Subtitle.UnsetMargins().Render("• still reported")
+		}
+		checked := ""
+		if d.item.Checked {
+			checked = " (was checked)"
+		}
+		s.WriteString("  " + status + "  " + theme.FileRef.Render(d.item.Title) + theme.Subtitle.UnsetMargins().Render(checked))
+		s.WriteString("\n")
+	}
+}
+
+// activeItems returns the items that have not been dismissed
+func (m model) activeItems() []ReviewItem {
+	items := []ReviewItem{}
+	for _, item := range m.items {
+		if !item.dismissed {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SARIF 2.1.0 document types, limited to the parts revyu fills in
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/rishavvajpayee/revyu"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind string `json:"kind"`
+}
+
+// sarifRuleID maps a finding's category to its rule id
+func sarifRuleID(category Category) string {
+	return "revyu/" + strings.ToLower(string(category))
+}
+
+// sarifLevel maps severity to the SARIF result level
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifRegionFor returns the line range of a finding, or nil without lines
+func sarifRegionFor(item ReviewItem) *sarifRegion {
+	if item.startLine == 0 {
+		return nil
+	}
+
+	region := &sarifRegion{StartLine: item.startLine}
+	if item.endLine > item.startLine {
+		region.EndLine = item.endLine
+	}
+
+	return region
+}
+
+// sarifResultFor converts one finding to a SARIF result. Code from the
+// review is shown in the Markdown message rather than offered as a fix: its
+// line range is approximate and the code is often only illustrative.
+func sarifResultFor(item ReviewItem) sarifResult {
+	message := item.content
+	if message == "" {
+		message = strings.TrimSpace(strings.ReplaceAll(item.title, "📄", ""))
+	}
+
+	markdown := ""
+	if len(item.codeBlocks) > 0 {
+		markdown = message
+		for _, code := range item.codeBlocks {
+			markdown += "\n\n```\n" + code + "\n```"
+		}
+	}
+
+	result := sarifResult{
+		RuleID:  sarifRuleID(item.category),
+		Level:   sarifLevel(item.severity),
+		Message: sarifMessage{Text: message, Markdown: markdown},
+		PartialFingerprints: map[string]string{
+			"revyuFingerprint/v1": itemFingerprint(item),
+		},
+	}
+
+	if item.file != "" {
+		artifact := sarifArtifactLocation{URI: item.file, URIBaseID: "%SRCROOT%"}
+		result.Locations = []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: sarifRegionFor(item)},
+		}}
+	}
+
+	if item.dismissed {
+		result.Suppressions = []sarifSuppression{{Kind: "external"}}
+	}
+
+	return result
+}
+
+// sarifReport renders the run as a SARIF 2.1.0 log
+func sarifReport(run reviewRun) (string, error) {
+	results := []sarifResult{}
+	for _, item := range run.items {
+		results = append(results, sarifResultFor(item))
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "revyu",
+				Version:        version,
+				InformationURI: toolURI,
+				Rules: []sarifRule{
+					{ID: sarifRuleID(CategoryIssue), Name: "Issue", ShortDescription: sarifMessage{Text: "Problem found by the AI review"}},
+					{ID: sarifRuleID(CategorySuggestion), Name: "Suggestion", ShortDescription: sarifMessage{Text: "Improvement suggested by the AI review"}},
+				},
+			}},
+			Results: results,
+		}},
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSarifLevel(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		want     string
+	}{
+		{SeverityHigh, "error"},
+		{SeverityMedium, "warning"},
+		{SeverityLow, "note"},
+		{"", "note"},
+	}
+
+	for _, tt := range tests {
+		if got := sarifLevel(tt.severity); got != tt.want {
+			t.Errorf("sarifLevel(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestSarifResultFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       ReviewItem
+		rule       string
+		level      string
+		message    string
+		region     *sarifRegion
+		markdown   string
+		suppressed bool
+	}{
+		{
+			name:    "issue with a line",
+			item:    ReviewItem{title: "1. **Leak** 📄 a.go:4", content: "File is not closed", severity: SeverityHigh, category: CategoryIssue, file: "a.go", startLine: 4},
+			rule:    "revyu/issue",
+			level:   "error",
+			message: "File is not closed",
+			region:  &sarifRegion{StartLine: 4},
+		},
+		{
+			name:    "issue with a range",
+			item:    ReviewItem{content: "Loop is quadratic", severity: SeverityMedium, category: CategoryIssue, file: "a.go", startLine: 4, endLine: 9},
+			rule:    "revyu/issue",
+			level:   "warning",
+			message: "Loop is quadratic",
+			region:  &sarifRegion{StartLine: 4, EndLine: 9},
+		},
+		{
+			name:     "suggestion code goes to the markdown message",
+			item:     ReviewItem{content: "Use errors.Is", severity: SeverityLow, category: CategorySuggestion, file: "a.go", startLine: 7, codeBlocks: []string{"old", "if errors.Is(err, fs.ErrNotExist) {"}},
+			rule:     "revyu/suggestion",
+			level:    "note",
+			message:  "Use errors.Is",
+			region:   &sarifRegion{StartLine: 7},
+			markdown: "Use errors.Is\n\n```\nold\n```\n\n```\nif errors.Is(err, fs.ErrNotExist) {\n```",
+		},
+		{
+			name:     "issue code goes to the markdown message",
+			item:     ReviewItem{content: "Race", severity: SeverityHigh, category: CategoryIssue, file: "a.go", startLine: 7, codeBlocks: []string{"mu.Lock()"}},
+			rule:     "revyu/issue",
+			level:    "error",
+			message:  "Race",
+			region:   &sarifRegion{StartLine: 7},
+			markdown: "Race\n\n```\nmu.Lock()\n```",
+		},
+		{
+			name:       "dismissed without a location",
+			item:       ReviewItem{title: "2. **Naming** 📄 somewhere", severity: SeverityLow, category: CategorySuggestion, dismissed: true},
+			rule:       "revyu/suggestion",
+			level:      "note",
+			message:    "2. **Naming**  somewhere",
+			suppressed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sarifResultFor(tt.item)
+			if result.RuleID != tt.rule || result.Level != tt.level || result.Message.Text != tt.message {
+				t.Errorf("result = %s %s %q, want %s %s %q", result.RuleID, result.Level, result.Message.Text, tt.rule, tt.level, tt.message)
+			}
+			if result.PartialFingerprints["revyuFingerprint/v1"] != itemFingerprint(tt.item) {
+				t.Errorf("fingerprint = %v", result.PartialFingerprints)
+			}
+
+			if tt.item.file == "" {
+				if len(result.Locations) != 0 {
+					t.Errorf("locations = %+v, want none", result.Locations)
+				}
+			} else {
+				if len(result.Locations) != 1 {
+					t.Fatalf("locations = %+v, want one", result.Locations)
+				}
+				location := result.Locations[0].PhysicalLocation
+				if location.ArtifactLocation.URI != tt.item.file {
+					t.Errorf("uri = %q, want %q", location.ArtifactLocation.URI, tt.item.file)
+				}
+				if !reflect.DeepEqual(location.Region, tt.region) {
+					t.Errorf("region = %+v, want %+v", location.Region, tt.region)
+				}
+			}
+
+			if result.Message.Markdown != tt.markdown {
+				t.Errorf("markdown = %q, want %q", result.Message.Markdown, tt.markdown)
+			}
+
+			if got := len(result.Suppressions) > 0; got != tt.suppressed {
+				t.Errorf("suppressed = %v, want %v", got, tt.suppressed)
+			}
+		})
+	}
+}
+
+func TestSarifReport(t *testing.T) {
+	run := reviewRun{items: []ReviewItem{
+		{content: "one", severity: SeverityHigh, category: CategoryIssue, file: "a.go", startLine: 1},
+		{content: "two", severity: SeverityLow, category: CategorySuggestion},
+	}}
+
+	out, err := sarifReport(run)
+	if err != nil {
+		t.Fatalf("sarifReport: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("sarifReport output is not JSON: %v", err)
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("log = %+v", log)
+	}
+	if got := len(log.Runs[0].Results); got != 2 {
+		t.Errorf("results = %d, want 2", got)
+	}
+	if got := len(log.Runs[0].Tool.Driver.Rules); got != 2 {
+		t.Errorf("rules = %d, want 2", got)
+	}
+	if strings.Contains(out, `"fixes"`) {
+		t.Errorf("SARIF output offers fixes:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("output does not end with a newline")
+	}
+}
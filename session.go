@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minSimilarity is the word overlap needed to carry a checked item over to a
+// changed diff when the fingerprints no longer match
+const minSimilarity = 0.5
+
+// SavedItem is the persisted checklist state of one finding
+type SavedItem struct {
+	Fingerprint string `json:"fingerprint"`
+	Title       string `json:"title"`
+	File        string `json:"file"`
+	Content     string `json:"content"`
+	Checked     bool   `json:"checked"`
+}
+
+// SessionState is the review and checklist progress saved between runs
+type SessionState struct {
+	Repo     string      `json:"repo"`
+	Target   string      `json:"target"`
+	DiffHash string      `json:"diff_hash"`
+	Provider string      `json:"provider"`
+	Model    string      `json:"model"`
+	Profile  string      `json:"profile"`
+	SavedAt  time.Time   `json:"saved_at"`
+	Review   string      `json:"review"`
+	Items    []SavedItem `json:"items"`
+	Chat     []Message   `json:"chat,omitempty"`
+}
+
+// checkedCount returns how many saved items were checked off
+func (s SessionState) checkedCount() int {
+	count := 0
+	for _, item := range s.Items {
+		if item.Checked {
+			count++
+		}
+	}
+
+	return count
+}
+
+// sessionsDir returns the directory that holds saved sessions for this repo
+func sessionsDir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "sessions"), nil
+}
+
+// sessionPrefix identifies a repo and target so sessions for other targets
+// are never offered
+func sessionPrefix(repo, target string) string {
+	return diffHash(repo + "\n" + target)[:12]
+}
+
+// sessionPath names the session of one diff reviewed with one provider, model
+// and profile, so changing any of them runs a fresh review
+func sessionPath(dir, repo, target, diff string, opts reviewOptions) string {
+	key := diffHash(diff + "\n" + opts.Provider + "\n" + opts.Model + "\n" + opts.Profile)
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", sessionPrefix(repo, target), key[:12]))
+}
+
+// sameRun reports whether a saved session reviewed this diff with these
+// options
+func (s SessionState) sameRun(diff string, opts reviewOptions) bool {
+	return s.DiffHash == diffHash(diff) && s.Provider == opts.Provider && s.Model == opts.Model && s.Profile == opts.Profile
+}
+
+func readSession(path string) (SessionState, error) {
+	var state SessionState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	return state, nil
+}
+
+// loadSession returns the saved state for exactly this target and diff
+// reviewed with the same options
+func loadSession(target, diff string, opts reviewOptions) (SessionState, bool) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return SessionState{}, false
+	}
+	repo, err := gitToplevel()
+	if err != nil {
+		return SessionState{}, false
+	}
+
+	state, err := readSession(sessionPath(dir, repo, target, diff, opts))
+	if err != nil || !state.sameRun(diff, opts) {
+		return SessionState{}, false
+	}
+
+	return state, true
+}
+
+// latestSession returns the most recently saved state for this target from a
+// different diff or different options, used to offer re-association after the
+// code or the model changed
+func latestSession(target, diff string, opts reviewOptions) (SessionState, bool) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return SessionState{}, false
+	}
+	repo, err := gitToplevel()
+	if err != nil {
+		return SessionState{}, false
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, sessionPrefix(repo, target)+"-*.json"))
+	states := []SessionState{}
+	for _, path := range matches {
+		state, err := readSession(path)
+		if err == nil && !state.sameRun(diff, opts) {
+			states = append(states, state)
+		}
+	}
+	if len(states) == 0 {
+		return SessionState{}, false
+	}
+
+	sort.Slice(states, func(a, b int) bool {
+		return states[a].SavedAt.After(states[b].SavedAt)
+	})
+
+	return states[0], true
+}
+
+// saveSession writes the current review and checklist state
+func (m model) saveSession() error {
+	if m.review == "" {
+		return nil
+	}
+
+	dir, err := sessionsDir()
+	if err != nil {
+		return err
+	}
+	repo, err := gitToplevel()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+
+	state := SessionState{
+		Repo:     repo,
+		Target:   m.filePath,
+		DiffHash: diffHash(m.diff),
+		Provider: m.opts.Provider,
+		Model:    m.opts.Model,
+		Profile:  m.opts.Profile,
+		SavedAt:  time.Now(),
+		Review:   m.review,
+		Items:    saveItems(m.items),
+		Chat:     m.chatLog,
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %v", err)
+	}
+	if err := os.WriteFile(sessionPath(dir, repo, m.filePath, m.diff, m.opts), data, 0o644); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+
+	return nil
+}
+
+func saveItems(items []ReviewItem) []SavedItem {
+	saved := make([]SavedItem, 0, len(items))
+	for _, item := range items {
+		saved = append(saved, SavedItem{
+			Fingerprint: itemFingerprint(item),
+			Title:       item.title,
+			File:        item.file,
+			Content:     item.content,
+			Checked:     item.checked,
+		})
+	}
+
+	return saved
+}
+
+// similarity returns the word overlap (Jaccard index) of two texts
+func similarity(a, b string) float64 {
+	wordsA := strings.Fields(normalizeText(a))
+	wordsB := strings.Fields(normalizeText(b))
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	setA := map[string]bool{}
+	for _, w := range wordsA {
+		setA[w] = true
+	}
+	union := len(setA)
+	shared := 0
+	seen := map[string]bool{}
+	for _, w := range wordsB {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if setA[w] {
+			shared++
+		} else {
+			union++
+		}
+	}
+
+	return float64(shared) / float64(union)
+}
+
+// matchSaved finds the current item that corresponds to a saved one, first by
+// fingerprint and then by the most similar text in the same file. It returns
+// -1 when nothing is close enough. Items already claimed are skipped.
+func matchSaved(items []ReviewItem, saved SavedItem, claimed map[int]bool) int {
+	for i, item := range items {
+		if !claimed[i] && itemFingerprint(item) == saved.Fingerprint {
+			return i
+		}
+	}
+
+	best, bestScore := -1, minSimilarity
+	for i, item := range items {
+		if claimed[i] || item.file != saved.File {
+			continue
+		}
+		score := similarity(item.title+" "+item.content, saved.Title+" "+saved.Content)
+		if score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	return best
+}
+
+// associate carries the checked state of saved items over to the current
+// items and returns how many were restored
+func associate(items []ReviewItem, saved []SavedItem) int {
+	claimed := map[int]bool{}
+	restored := 0
+	for _, s := range saved {
+		idx := matchSaved(items, s, claimed)
+		if idx < 0 {
+			continue
+		}
+		claimed[idx] = true
+		if s.Checked {
+			items[idx].checked = true
+			restored++
+		}
+	}
+
+	return restored
+}
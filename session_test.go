@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "the error is ignored", "the error is ignored", 1},
+		{"case and spacing", "The  error is\nIGNORED", "the error is ignored", 1},
+		{"disjoint", "nil pointer", "missing lock", 0},
+		{"half", "a b c", "a b d e", 2.0 / 5.0},
+		{"repeated words", "a a b", "a b b", 1},
+		{"empty", "", "anything", 0},
+		{"both empty", "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssociate(t *testing.T) {
+	items := func() []ReviewItem {
+		return []ReviewItem{
+			{number: 1, file: "main.go", title: "1. **Nil check** 📄 main.go:10", content: "The error returned by open is ignored"},
+			{number: 2, file: "main.go", title: "2. **Lock** 📄 main.go:20", content: "The mutex is never unlocked on the error path"},
+			{number: 3, file: "cli.go", title: "3. **Flag** 📄 cli.go:5", content: "The flag default is wrong"},
+		}
+	}
+	saved := func(item ReviewItem, checked bool) SavedItem {
+		return SavedItem{Fingerprint: itemFingerprint(item), Title: item.title, File: item.file, Content: item.content, Checked: checked}
+	}
+
+	tests := []struct {
+		name     string
+		saved    func(current []ReviewItem) []SavedItem
+		restored int
+		checked  []int
+	}{
+		{
+			name:     "same fingerprint",
+			saved:    func(current []ReviewItem) []SavedItem { return []SavedItem{saved(current[1], true)} },
+			restored: 1,
+			checked:  []int{2},
+		},
+		{
+			name: "reworded in the same file",
+			saved: func(current []ReviewItem) []SavedItem {
+				s := saved(current[0], true)
+				s.Fingerprint = "changed"
+				s.Content = "The error returned by os.Open is ignored here"
+				return []SavedItem{s}
+			},
+			restored: 1,
+			checked:  []int{1},
+		},
+		{
+			name: "similar text in another file",
+			saved: func(current []ReviewItem) []SavedItem {
+				s := saved(current[2], true)
+				s.Fingerprint = "changed"
+				s.File = "other.go"
+				return []SavedItem{s}
+			},
+		},
+		{
+			name: "unrelated text",
+			saved: func(current []ReviewItem) []SavedItem {
+				return []SavedItem{{Fingerprint: "gone", File: "main.go", Title: "Typo", Content: "Spelling in a comment", Checked: true}}
+			},
+		},
+		{
+			name:  "unchecked items are matched but not restored",
+			saved: func(current []ReviewItem) []SavedItem { return []SavedItem{saved(current[0], false)} },
+		},
+		{
+			name: "an item is claimed once",
+			saved: func(current []ReviewItem) []SavedItem {
+				first := saved(current[0], true)
+				second := first
+				second.Fingerprint = "changed"
+				return []SavedItem{first, second}
+			},
+			restored: 1,
+			checked:  []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := items()
+			if got := associate(current, tt.saved(items())); got != tt.restored {
+				t.Errorf("associate restored %d, want %d", got, tt.restored)
+			}
+
+			want := map[int]bool{}
+			for _, n := range tt.checked {
+				want[n] = true
+			}
+			for _, item := range current {
+				if item.checked != want[item.number] {
+					t.Errorf("item %d checked = %v, want %v", item.number, item.checked, want[item.number])
+				}
+			}
+		})
+	}
+}
+
+func TestSessionPathDependsOnOptions(t *testing.T) {
+	opts := reviewOptions{Provider: "openai", Model: "gpt-4o", Profile: "default"}
+	path := sessionPath("dir", "/repo", ".", "diff", opts)
+
+	for _, other := range []reviewOptions{
+		{Provider: "openai", Model: "gpt-4o-mini", Profile: "default"},
+		{Provider: "openai", Model: "gpt-4o", Profile: "security"},
+	} {
+		if sessionPath("dir", "/repo", ".", "diff", other) == path {
+			t.Errorf("%+v shares the session of %+v", other, opts)
+		}
+	}
+	if sessionPath("dir", "/repo", ".", "diff", opts) != path {
+		t.Errorf("sessionPath is not stable")
+	}
+}
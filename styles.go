@@ -1,57 +1,221 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette is the set of colours a theme is built from. Empty colours are left
+// unset, which is how the monochrome theme renders without any colour.
+type Palette struct {
+	Accent     string `yaml:"accent" toml:"accent"`
+	Muted      string `yaml:"muted" toml:"muted"`
+	Border     string `yaml:"border" toml:"border"`
+	Text       string `yaml:"text" toml:"text"`
+	Error      string `yaml:"error" toml:"error"`
+	Success    string `yaml:"success" toml:"success"`
+	Section    string `yaml:"section" toml:"section"`
+	Heading    string `yaml:"heading" toml:"heading"`
+	FileRef    string `yaml:"file_ref" toml:"file_ref"`
+	Code       string `yaml:"code" toml:"code"`
+	CodeBg     string `yaml:"code_bg" toml:"code_bg"`
+	SelectedBg string `yaml:"selected_bg" toml:"selected_bg"`
+	High       string `yaml:"high" toml:"high"`
+	HighBg     string `yaml:"high_bg" toml:"high_bg"`
+	Medium     string `yaml:"medium" toml:"medium"`
+	MediumBg   string `yaml:"medium_bg" toml:"medium_bg"`
+	Low        string `yaml:"low" toml:"low"`
+	LowBg      string `yaml:"low_bg" toml:"low_bg"`
+}
 
-// Styles for the TUI
 var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#7D56F4")).
-			MarginTop(1).
-			MarginBottom(1)
-
-	subtitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6272A4")).
-			MarginBottom(1)
-
-	separatorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#44475A"))
-
-	errorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF0000")).
-			MarginTop(1).
-			MarginBottom(1)
-
-	successStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#04B575")).
-			MarginTop(1)
-
-	contentStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F8F8F2")).
-			MarginLeft(2).
-			MarginBottom(0)
-
-	severityHighStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FF0000")).
-				Background(lipgloss.Color("#4a0000"))
-
-	severityMediumStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFA500")).
-				Background(lipgloss.Color("#4a3000"))
-
-	severityLowStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFD700")).
-				Background(lipgloss.Color("#3a3000"))
-
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
-			Padding(1, 2).
-			MarginTop(1).
-			MarginBottom(1)
+	darkPalette = Palette{
+		Accent: "#7D56F4", Muted: "#6272A4", Border: "#44475A", Text: "#F8F8F2",
+		Error: "#FF0000", Success: "#04B575", Section: "#BD93F9", Heading: "#50FA7B",
+		FileRef: "#8BE9FD", Code: "#50FA7B", CodeBg: "#282A36", SelectedBg: "#44475A",
+		High: "#FF0000", HighBg: "#4a0000", Medium: "#FFA500", MediumBg: "#4a3000",
+		Low: "#FFD700", LowBg: "#3a3000",
+	}
+
+	lightPalette = Palette{
+		Accent: "#5A32C8", Muted: "#5C6370", Border: "#B0B4BE", Text: "#1F2328",
+		Error: "#C62828", Success: "#1B7F3B", Section: "#6F42C1", Heading: "#1B7F3B",
+		FileRef: "#0969DA", Code: "#24292F", CodeBg: "#EAEEF2", SelectedBg: "#DDE3EA",
+		High: "#FFFFFF", HighBg: "#C62828", Medium: "#1F2328", MediumBg: "#F5A623",
+		Low: "#1F2328", LowBg: "#F2D94E",
+	}
+
+	highContrastPalette = Palette{
+		Accent: "#FFFF00", Muted: "#FFFFFF", Border: "#FFFFFF", Text: "#FFFFFF",
+		Error: "#FF5555", Success: "#00FF00", Section: "#FFFF00", Heading: "#00FFFF",
+		FileRef: "#00FFFF", Code: "#FFFFFF", CodeBg: "#000000", SelectedBg: "#0000AA",
+		High: "#FFFFFF", HighBg: "#CC0000", Medium: "#000000", MediumBg: "#FFAA00",
+		Low: "#000000", LowBg: "#FFFF00",
+	}
+
+	monochromePalette = Palette{}
 )
+
+// builtinPalettes lists the themes that can be selected by name
+var builtinPalettes = map[string]Palette{
+	"dark":          darkPalette,
+	"light":         lightPalette,
+	"high-contrast": highContrastPalette,
+	"monochrome":    monochromePalette,
+}
+
+// asciiReplacer swaps emoji and box-drawing characters for plain ASCII so the
+// output stays readable for screen readers and limited terminals
+var asciiReplacer = strings.NewReplacer(
+	"🔍 ", "", "📄 ", "", "📄", "", "❌ ", "",
+	"─", "-", "•", "*", "▶", ">", "▸", ">", "▾", "v", "✓", "x", "█", "_",
+	"↑", "up", "↓", "down", "⚠️", "!", "⚠", "!", "…", "...",
+)
+
+// asciiBorder draws the outer box without box-drawing characters
+var asciiBorder = lipgloss.Border{
+	Top: "-", Bottom: "-", Left: "|", Right: "|",
+	TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+}
+
+// Theme holds every style used by the TUI and the markdown formatter
+type Theme struct {
+	Title          lipgloss.Style
+	Subtitle       lipgloss.Style
+	Separator      lipgloss.Style
+	Error          lipgloss.Style
+	Success        lipgloss.Style
+	Content        lipgloss.Style
+	SeverityHigh   lipgloss.Style
+	SeverityMedium lipgloss.Style
+	SeverityLow    lipgloss.Style
+	Box            lipgloss.Style
+	SectionTitle   lipgloss.Style
+	Heading        lipgloss.Style
+	CodeBlock      lipgloss.Style
+	Normal         lipgloss.Style
+	FileRef        lipgloss.Style
+	ItemHeader     lipgloss.Style
+	Group          lipgloss.Style
+	Selected       lipgloss.Style
+	ChatUser       lipgloss.Style
+	ChatBot        lipgloss.Style
+	Spinner        lipgloss.Style
+	Help           help.Styles
+	ASCII          bool
+}
+
+// fg sets a foreground colour unless the palette leaves it empty
+func fg(style lipgloss.Style, color string) lipgloss.Style {
+	if color == "" {
+		return style
+	}
+	return style.Foreground(lipgloss.Color(color))
+}
+
+// bg sets a background colour unless the palette leaves it empty
+func bg(style lipgloss.Style, color string) lipgloss.Style {
+	if color == "" {
+		return style
+	}
+	return style.Background(lipgloss.Color(color))
+}
+
+// newTheme builds every style from a palette. Without a selection colour the
+// cursor line is shown in reverse video instead.
+func newTheme(p Palette, ascii bool) Theme {
+	base := lipgloss.NewStyle()
+
+	selected := bg(base, p.SelectedBg)
+	if p.SelectedBg == "" {
+		selected = base.Reverse(true)
+	}
+
+	border := lipgloss.RoundedBorder()
+	if ascii {
+		border = asciiBorder
+	}
+
+	helpStyles := help.New().Styles
+	helpStyles.ShortKey = fg(base, p.Muted)
+	helpStyles.ShortDesc = fg(base, p.Muted).Italic(true)
+	helpStyles.ShortSeparator = fg(base, p.Border)
+	helpStyles.FullKey = fg(base, p.Muted)
+	helpStyles.FullDesc = fg(base, p.Muted).Italic(true)
+	helpStyles.FullSeparator = fg(base, p.Border)
+	helpStyles.Ellipsis = fg(base, p.Border)
+
+	return Theme{
+		Title:          fg(base.Bold(true), p.Accent).MarginTop(1).MarginBottom(1),
+		Subtitle:       fg(base, p.Muted).MarginBottom(1),
+		Separator:      fg(base, p.Border),
+		Error:          fg(base.Bold(true), p.Error).MarginTop(1).MarginBottom(1),
+		Success:        fg(base.Bold(true), p.Success).MarginTop(1),
+		Content:        fg(base, p.Text).MarginLeft(2).MarginBottom(0),
+		SeverityHigh:   bg(fg(base.Bold(true), p.High), p.HighBg),
+		SeverityMedium: bg(fg(base.Bold(true), p.Medium), p.MediumBg),
+		SeverityLow:    bg(fg(base.Bold(true), p.Low), p.LowBg),
+		Box:            base.Border(border).BorderForeground(lipgloss.Color(p.Accent)).Padding(1, 2).MarginTop(1).MarginBottom(1),
+		SectionTitle:   fg(base.Bold(true), p.Section).MarginTop(1).MarginBottom(1),
+		Heading:        fg(base.Bold(true), p.Heading),
+		CodeBlock:      bg(fg(base, p.Code), p.CodeBg).Padding(0, 1),
+		Normal:         fg(base, p.Text),
+		FileRef:        fg(base, p.FileRef),
+		ItemHeader:     fg(base.Bold(true), p.Text),
+		Group:          fg(base.Bold(true), p.Section),
+		Selected:       selected,
+		ChatUser:       fg(base.Bold(true), p.FileRef),
+		ChatBot:        fg(base.Bold(true), p.Heading),
+		Spinner:        fg(base, p.Accent),
+		Help:           helpStyles,
+		ASCII:          ascii,
+	}
+}
+
+// plain strips emoji and box-drawing characters when ASCII mode is on
+func (t Theme) plain(text string) string {
+	if !t.ASCII {
+		return text
+	}
+	return asciiReplacer.Replace(text)
+}
+
+// spinnerType picks a spinner that only uses ASCII characters in ASCII mode
+func (t Theme) spinnerType() spinner.Spinner {
+	if t.ASCII {
+		return spinner.Line
+	}
+	return spinner.Dot
+}
+
+// resolveTheme picks the palette named in the config. "auto" (the default)
+// follows the terminal background, and NO_COLOR always wins.
+func resolveTheme(name string, custom map[string]Palette, ascii bool) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return newTheme(monochromePalette, ascii), nil
+	}
+
+	if name == "" || name == "auto" {
+		if lipgloss.HasDarkBackground() {
+			return newTheme(darkPalette, ascii), nil
+		}
+		return newTheme(lightPalette, ascii), nil
+	}
+
+	if p, ok := custom[name]; ok {
+		return newTheme(p, ascii), nil
+	}
+	if p, ok := builtinPalettes[name]; ok {
+		return newTheme(p, ascii), nil
+	}
+
+	return newTheme(darkPalette, ascii), fmt.Errorf("unknown theme %q", name)
+}
+
+// theme holds the active styles; main replaces it once the config is loaded
+var theme = newTheme(darkPalette, false)
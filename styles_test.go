@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// nonASCII returns the runes of s outside of ASCII
+func nonASCII(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 127 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func TestPlainReplacesEveryGlyph(t *testing.T) {
+	ascii := newTheme(darkPalette, true)
+	for _, text := range []string{
+		"⚠️  Warning: this binary has an API key embedded at build time.",
+		"❌ Error: no API key found",
+		"… 3 older sessions",
+		"a very long title…",
+		"🔍 Revyu ─ • ▶ ▸ ▾ ✓ █ ↑/k ↓/j 📄 main.go:1",
+	} {
+		if got := nonASCII(ascii.plain(text)); got != "" {
+			t.Errorf("plain(%q) keeps %q", text, got)
+		}
+	}
+}
+
+func TestASCIIView(t *testing.T) {
+	saved := theme
+	theme = newTheme(darkPalette, true)
+	t.Cleanup(func() { theme = saved })
+
+	m := testModel()
+	m.help = newHelp()
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = next.(model)
+	m.items[0].checked = true
+	m.items[1].title = "2. **A title long enough to be cut short in the summary line of the list** 📄 b.go:2"
+
+	screens := map[string]model{"list": m}
+
+	help := m
+	help.showHelp = true
+	screens["help"] = help
+
+	chat := m
+	chat.chatOpen = true
+	chat.chatLog = []Message{{Role: "user", Content: "why?"}, {Role: "assistant", Content: "because"}}
+	screens["chat"] = chat
+
+	expanded := press(t, m, "enter")
+	screens["expanded"] = expanded
+
+	for name, screen := range screens {
+		if got := nonASCII(screen.View()); got != "" {
+			t.Errorf("%s view keeps %q in ASCII mode", name, got)
+		}
+	}
+}
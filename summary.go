@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// extractSection returns the body of a top-level review section such as
+// "Summary", stopping at the next section heading
+func extractSection(review, name string) string {
+	lines := strings.Split(review, "\n")
+	var body []string
+	inSection := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		heading := strings.Trim(strings.TrimLeft(trimmed, "#0123456789. "), "*: ")
+		isHeading := strings.HasPrefix(trimmed, "#") ||
+			(strings.HasPrefix(trimmed, "**") && strings.Contains(trimmed[2:], "**")) ||
+			(len(trimmed) > 2 && trimmed[0] >= '1' && trimmed[0] <= '9' && trimmed[1] == '.')
+
+		if isHeading {
+			if inSection {
+				break
+			}
+			if strings.HasPrefix(strings.ToLower(heading), strings.ToLower(name)) {
+				inSection = true
+				// The text may follow the heading on the same line
+				if idx := strings.Index(trimmed, ":"); idx >= 0 {
+					rest := strings.TrimSpace(strings.Trim(trimmed[idx+1:], "* "))
+					if rest != "" {
+						body = append(body, rest)
+					}
+				}
+			}
+			continue
+		}
+
+		if inSection && trimmed != "" {
+			body = append(body, cleanInlineMarkdown(strings.TrimLeft(trimmed, "-* ")))
+		}
+	}
+
+	return strings.Join(body, " ")
+}
+
+// highUnchecked counts High severity findings that are neither checked nor dismissed
+func (m model) highUnchecked() int {
+	count := 0
+	for _, item := range m.items {
+		if item.severity == SeverityHigh && !item.checked && !item.dismissed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// exitSummary renders a compact report for the terminal scrollback once the
+// TUI has closed: the review summary, what is left to do and what was done
+func exitSummary(m model) string {
+	var b strings.Builder
+	width := m.maxWidth()
+
+	b.WriteString(theme.Success.UnsetMargins().Render(fmt.Sprintf("Revyu review of %s", m.filePath)))
+	b.WriteString("\n")
+
+	if summary := extractSection(m.review, "Summary"); summary != "" {
+		b.WriteString(wrapText(summary, width))
+		b.WriteString("\n")
+	}
+
+	if len(m.items) == 0 {
+		return theme.plain(b.String())
+	}
+
+	resolved, total := 0, 0
+	var open []ReviewItem
+	for _, item := range m.items {
+		if item.dismissed {
+			continue
+		}
+		total++
+		if item.checked {
+			resolved++
+		} else {
+			open = append(open, item)
+		}
+	}
+	sortable := make([]int, len(open))
+	for i := range sortable {
+		sortable[i] = i
+	}
+	sortItems(sortable, open, sortSeverityDesc)
+
+	if len(open) > 0 {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("Unresolved (%d):\n", len(open)))
+		for _, idx := range sortable {
+			item := open[idx]
+			location := strings.TrimSpace(strings.ReplaceAll(item.title, "📄", ""))
+			if item.file != "" {
+				location = item.file
+				if item.startLine > 0 {
+					location = fmt.Sprintf("%s:%d", item.file, item.startLine)
+				}
+			}
+			fmt.Fprintf(&b, "  %s #%d %s", severityBadge(item.severity), item.number, location)
+			if item.content != "" {
+				fmt.Fprintf(&b, "  %s", firstSentence(item.content, width-len(location)-16))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Resolved %d of %d", resolved, total)
+	if dismissed := m.dismissedCount(); dismissed > 0 {
+		fmt.Fprintf(&b, "  •  %d dismissed", dismissed)
+	}
+	b.WriteString("\n")
+
+	return theme.plain(b.String())
+}
+
+// severityBadge renders the short coloured severity label
+func severityBadge(severity Severity) string {
+	switch severity {
+	case SeverityHigh:
+		return theme.SeverityHigh.Render(" HIGH ")
+	case SeverityMedium:
+		return theme.SeverityMedium.Render(" MED  ")
+	default:
+		return theme.SeverityLow.Render(" LOW  ")
+	}
+}
+
+// firstSentence shortens text to its first sentence, capped at max characters
+func firstSentence(text string, max int) string {
+	if idx := strings.Index(text, ". "); idx >= 0 {
+		text = text[:idx+1]
+	}
+	if max < 20 {
+		max = 20
+	}
+	runes := []rune(text)
+	if len(runes) > max {
+		return string(runes[:max-1]) + "…"
+	}
+
+	return text
+}
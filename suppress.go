@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// suppressionsFile is the repo-relative path where dismissed findings are stored
+const suppressionsFile = ".revyu/suppressions"
+
+// dismissReason records why a finding was dismissed
+type dismissReason string
+
+const (
+	reasonFalsePositive dismissReason = "false-positive"
+	reasonWontFix       dismissReason = "wont-fix"
+	reasonIntentional   dismissReason = "intentional"
+)
+
+// Suppression is a dismissed finding that should stay hidden in later reviews
+type Suppression struct {
+	fingerprint string
+	reason      dismissReason
+	file        string
+	date        string
+	title       string
+}
+
+// normalizeText lowercases text and collapses whitespace so cosmetic changes
+// in the model's wording do not change a fingerprint
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// itemFingerprint identifies a finding by its file, code context and text.
+// Line numbers are left out on purpose so the fingerprint survives edits
+// elsewhere in the file.
+func itemFingerprint(item ReviewItem) string {
+	code := ""
+	if len(item.codeBlocks) > 0 {
+		code = item.codeBlocks[0]
+	}
+
+	text := item.content
+	if text == "" {
+		text = item.title
+	}
+
+	sum := sha256.Sum256([]byte(item.file + "\n" + normalizeText(code) + "\n" + normalizeText(text)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func suppressionsPath() (string, error) {
+	root, err := gitToplevel()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, suppressionsFile), nil
+}
+
+// loadSuppressions reads the repo's suppressions file. A missing file means
+// nothing has been dismissed yet.
+func loadSuppressions() ([]Suppression, error) {
+	path, err := suppressionsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []Suppression{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", suppressionsFile, err)
+	}
+	defer file.Close()
+
+	suppressions := []Suppression{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 5)
+		for len(fields) < 5 {
+			fields = append(fields, "")
+		}
+		suppressions = append(suppressions, Suppression{
+			fingerprint: fields[0],
+			reason:      dismissReason(fields[1]),
+			file:        fields[2],
+			date:        fields[3],
+			title:       fields[4],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", suppressionsFile, err)
+	}
+
+	return suppressions, nil
+}
+
+// saveSuppressions rewrites the suppressions file, one tab-separated entry per line
+func saveSuppressions(suppressions []Suppression) error {
+	path, err := suppressionsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# revyu suppressions: fingerprint, reason, file, date, title (tab-separated)\n")
+	for _, s := range suppressions {
+		title := strings.ReplaceAll(s.title, "\t", " ")
+		fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%s\n", s.fingerprint, s.reason, s.file, s.date, title)
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", suppressionsFile, err)
+	}
+
+	return nil
+}
+
+// isSuppressed reports whether a finding matches a stored dismissal
+func isSuppressed(item ReviewItem, suppressions []Suppression) bool {
+	fingerprint := itemFingerprint(item)
+	for _, s := range suppressions {
+		if s.fingerprint == fingerprint {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applySuppressions hides every item that was dismissed in an earlier run
+func (m *model) applySuppressions() {
+	for i := range m.items {
+		m.items[i].dismissed = isSuppressed(m.items[i], m.suppressions)
+	}
+}
+
+// dismissItem hides an item and records the dismissal in the repo
+func (m *model) dismissItem(idx int, reason dismissReason) error {
+	item := m.items[idx]
+	m.items[idx].dismissed = true
+	m.suppressions = append(m.suppressions, Suppression{
+		fingerprint: itemFingerprint(item),
+		reason:      reason,
+		file:        item.file,
+		date:        time.Now().Format("2006-01-02"),
+		title:       item.title,
+	})
+
+	return saveSuppressions(m.suppressions)
+}
+
+// undismiss removes a stored dismissal and shows matching items again
+func (m *model) undismiss(pos int) error {
+	if pos < 0 || pos >= len(m.suppressions) {
+		return nil
+	}
+
+	m.suppressions = append(m.suppressions[:pos:pos], m.suppressions[pos+1:]...)
+	m.applySuppressions()
+
+	return saveSuppressions(m.suppressions)
+}
+
+// dismissedCount returns how many items of the current review are hidden
+func (m model) dismissedCount() int {
+	count := 0
+	for _, item := range m.items {
+		if item.dismissed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// updateDismiss waits for the user to pick a reason for the pending dismissal
+func (m model) updateDismiss(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	reasons := map[string]dismissReason{
+		"1": reasonFalsePositive,
+		"2": reasonWontFix,
+		"3": reasonIntentional,
+	}
+
+	m.dismissing = false
+	reason, ok := reasons[msg.String()]
+	if !ok {
+		return m, nil
+	}
+
+	rows := m.rows()
+	if m.cursorPos < len(rows) && rows[m.cursorPos].item >= 0 {
+		m.suppressErr = m.dismissItem(rows[m.cursorPos].item, reason)
+		m.clampCursor()
+	}
+
+	return m, nil
+}
+
+// updateSuppressions handles the list of stored dismissals
+func (m model) updateSuppressions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		cmd := m.quit()
+		return m, cmd
+	case key.Matches(msg, m.keys.Back, m.keys.Dismissed):
+		m.showSuppressed = false
+		m.clampCursor()
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = true
+	case key.Matches(msg, m.keys.Up):
+		if m.suppressCursor > 0 {
+			m.suppressCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.suppressCursor < len(m.suppressions)-1 {
+			m.suppressCursor++
+		}
+	case key.Matches(msg, m.keys.Undismiss):
+		m.suppressErr = m.undismiss(m.suppressCursor)
+		if m.suppressCursor >= len(m.suppressions) && m.suppressCursor > 0 {
+			m.suppressCursor--
+		}
+	}
+
+	return m, nil
+}
+
+// renderSuppressions lists the stored dismissals with their reasons
+func (m model) renderSuppressions(s *strings.Builder) {
+	s.WriteString(theme.Subtitle.Render(fmt.Sprintf("%d dismissed findings in %s", len(m.suppressions), suppressionsFile)))
+	s.WriteString("\n")
+
+	if len(m.suppressions) == 0 {
+		s.WriteString(theme.Content.Render("Nothing has been dismissed yet"))
+		s.WriteString("\n")
+	}
+
+	for i, sup := range m.suppressions {
+		cursor := "  "
+		lineStyle := theme.Normal
+		if i == m.suppressCursor {
+			cursor = "▶ "
+			lineStyle = lineStyle.Inherit(theme.Selected)
+		}
+		s.WriteString(lineStyle.Render(fmt.Sprintf("%s[%s] %s", cursor, sup.reason, sup.title)))
+		s.WriteString("\n")
+		s.WriteString(theme.Subtitle.Render(fmt.Sprintf("    %s  •  dismissed %s", sup.fingerprint, sup.date)))
+		s.WriteString("\n")
+	}
+
+	if m.suppressErr != nil {
+		s.WriteString(theme.Error.Render(m.suppressErr.Error()))
+		s.WriteString("\n")
+	}
+}
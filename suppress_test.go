@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// initTestRepo creates an empty git repository and makes it the working
+// directory for the rest of the test
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init failed: %v: %s", err, out)
+	}
+	t.Chdir(dir)
+
+	root, err := gitToplevel()
+	if err != nil {
+		t.Fatalf("gitToplevel: %v", err)
+	}
+
+	return root
+}
+
+func TestItemFingerprint(t *testing.T) {
+	base := ReviewItem{
+		file:       "main.go",
+		title:      "1. **Nil check** 📄 main.go:10",
+		content:    "The error is ignored.",
+		codeBlocks: []string{"if err != nil {\n\treturn err\n}"},
+		startLine:  10,
+		endLine:    12,
+	}
+
+	tests := []struct {
+		name string
+		edit func(item *ReviewItem)
+		same bool
+	}{
+		{"unchanged", func(item *ReviewItem) {}, true},
+		{"moved lines", func(item *ReviewItem) { item.startLine, item.endLine = 40, 42 }, true},
+		{"checked", func(item *ReviewItem) { item.checked = true }, true},
+		{"case and spacing", func(item *ReviewItem) { item.content = "  the ERROR is\n ignored. " }, true},
+		{"reformatted code", func(item *ReviewItem) { item.codeBlocks = []string{"if err != nil {  return err }"} }, true},
+		{"other title", func(item *ReviewItem) { item.title = "2. **Renamed** 📄 main.go:40" }, true},
+		{"other file", func(item *ReviewItem) { item.file = "cli.go" }, false},
+		{"other text", func(item *ReviewItem) { item.content = "The error is wrapped twice." }, false},
+		{"other code", func(item *ReviewItem) { item.codeBlocks = []string{"panic(err)"} }, false},
+		{"no code", func(item *ReviewItem) { item.codeBlocks = nil }, false},
+	}
+
+	want := itemFingerprint(base)
+	if len(want) != 16 {
+		t.Fatalf("fingerprint %q has length %d, want 16", want, len(want))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := base
+			item.codeBlocks = append([]string(nil), base.codeBlocks...)
+			tt.edit(&item)
+			if got := itemFingerprint(item); (got == want) != tt.same {
+				t.Errorf("itemFingerprint = %s, base %s, want same = %v", got, want, tt.same)
+			}
+		})
+	}
+}
+
+func TestItemFingerprintFallsBackToTitle(t *testing.T) {
+	a := ReviewItem{file: "main.go", title: "1. **Leak** 📄 main.go:3"}
+	b := ReviewItem{file: "main.go", title: "1. **Race** 📄 main.go:3"}
+	if itemFingerprint(a) == itemFingerprint(b) {
+		t.Errorf("items without content share the fingerprint %s", itemFingerprint(a))
+	}
+}
+
+func TestLoadSuppressions(t *testing.T) {
+	root := initTestRepo(t)
+
+	got, err := loadSuppressions()
+	if err != nil {
+		t.Fatalf("loadSuppressions without a file: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("loadSuppressions without a file = %v, want none", got)
+	}
+
+	path := filepath.Join(root, suppressionsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "# revyu suppressions\n" +
+		"\n" +
+		"0123456789abcdef\tfalse-positive\tmain.go\t2026-01-02\tNil check\tstill the title\n" +
+		"fedcba9876543210\twont-fix\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = loadSuppressions()
+	if err != nil {
+		t.Fatalf("loadSuppressions: %v", err)
+	}
+	want := []Suppression{
+		{fingerprint: "0123456789abcdef", reason: reasonFalsePositive, file: "main.go", date: "2026-01-02", title: "Nil check\tstill the title"},
+		{fingerprint: "fedcba9876543210", reason: reasonWontFix},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadSuppressions = %+v, want %+v", got, want)
+	}
+}
+
+func TestSaveSuppressionsRoundTrip(t *testing.T) {
+	initTestRepo(t)
+
+	saved := []Suppression{
+		{fingerprint: "0123456789abcdef", reason: reasonIntentional, file: "a.go", date: "2026-01-02", title: "Tabs\tin the title"},
+	}
+	if err := saveSuppressions(saved); err != nil {
+		t.Fatalf("saveSuppressions: %v", err)
+	}
+
+	got, err := loadSuppressions()
+	if err != nil {
+		t.Fatalf("loadSuppressions: %v", err)
+	}
+	want := []Suppression{
+		{fingerprint: "0123456789abcdef", reason: reasonIntentional, file: "a.go", date: "2026-01-02", title: "Tabs in the title"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+
+	item := ReviewItem{file: "a.go", content: "dismissed"}
+	if isSuppressed(item, got) {
+		t.Errorf("isSuppressed matched an item with another fingerprint")
+	}
+	got[0].fingerprint = itemFingerprint(item)
+	if !isSuppressed(item, got) {
+		t.Errorf("isSuppressed did not match the item's fingerprint")
+	}
+}
@@ -3,16 +3,18 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-func initialModel(apiKey, filePath, diff string) model {
+func initialModel(apiKey, filePath, diff string, keys keyMap) model {
 	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	s.Spinner = theme.spinnerType()
+	s.Style = theme.Spinner
 
 	return model{
 		spinner:   s,
@@ -20,8 +22,12 @@ func initialModel(apiKey, filePath, diff string) model {
 		filePath:  filePath,
 		diff:      diff,
 		apiKey:    apiKey,
+		opts:      defaultReviewOptions(),
 		items:     []ReviewItem{},
 		cursorPos: 0,
+		chatFocus: -1,
+		keys:      keys,
+		help:      newHelp(),
 		width:     120,
 		height:    40,
 	}
@@ -36,11 +42,36 @@ func (m model) Init() tea.Cmd {
 
 func (m model) getReview() tea.Cmd {
 	return func() tea.Msg {
-		review, err := reviewDiff(m.apiKey, m.diff)
-		return reviewMsg{review: review, err: err}
+		if state, ok := loadSession(m.filePath, m.diff, m.opts); ok {
+			return reviewMsg{review: state.Review, restored: &state}
+		}
+
+		var (
+			review string
+			err    error
+		)
+		if m.patches != nil {
+			review, _, err = reviewSeries(m.apiKey, m.opts, m.patches)
+		} else {
+			review, _, err = reviewDiff(m.apiKey, m.opts, m.diff)
+		}
+		msg := reviewMsg{review: review, err: err}
+		if err == nil {
+			if previous, ok := latestSession(m.filePath, m.diff, m.opts); ok && previous.checkedCount() > 0 {
+				msg.previous = &previous
+			}
+		}
+		return msg
 	}
 }
 
+// quit saves the checklist progress and stops the program
+func (m *model) quit() tea.Cmd {
+	m.quitting = true
+	m.sessionErr = m.saveSession()
+	return tea.Quit
+}
+
 // Update handles messages and updates the model state
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -50,53 +81,248 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		if msg.String() == "q" || msg.String() == "ctrl+c" {
-			m.quitting = true
-			return m, tea.Quit
+		m.status = ""
+		if m.showHelp {
+			// The help overlay covers every screen, so it takes the keys
+			// before the screen behind it
+			switch {
+			case msg.Type == tea.KeyCtrlC:
+				cmd := m.quit()
+				return m, cmd
+			case key.Matches(msg, m.keys.Help, m.keys.Back, m.keys.Quit):
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		if m.chatOpen {
+			return m.updateChat(msg)
+		}
+		if m.rerunForm != nil {
+			return m.updateRerun(msg)
+		}
+		if m.dismissing {
+			return m.updateDismiss(msg)
+		}
+		if m.showSuppressed {
+			return m.updateSuppressions(msg)
+		}
+		if m.offerRestore != nil {
+			switch msg.String() {
+			case "y":
+				m.restored = associate(m.items, m.offerRestore.Items)
+				m.restoredAt = m.offerRestore.SavedAt
+				m.offerRestore = nil
+				m.sessionErr = m.saveSession()
+				return m, nil
+			case "n", "esc":
+				m.offerRestore = nil
+				return m, nil
+			}
+		}
+
+		if m.confirming {
+			m.confirming = false
+			if msg.String() == "y" {
+				cmd := m.quit()
+				return m, cmd
+			}
+			return m, nil
+		}
+
+		if msg.String() == "ctrl+c" || m.loading && key.Matches(msg, m.keys.Quit) {
+			cmd := m.quit()
+			return m, cmd
 		}
 
 		if !m.loading {
-			switch msg.String() {
-			case "up", "k":
+			visible := m.visibleItems()
+			rows := m.rows()
+			switch {
+			case key.Matches(msg, m.keys.Up):
 				if m.cursorPos > 0 {
 					m.cursorPos--
 				}
-			case "down", "j":
-				if m.cursorPos < len(m.items)-1 {
+			case key.Matches(msg, m.keys.Down):
+				if m.cursorPos < len(rows)-1 {
 					m.cursorPos++
 				}
-			case " ", "x":
-				if len(m.items) > 0 && m.cursorPos < len(m.items) {
-					// check if the position is already checked
-					if m.items[m.cursorPos].checked {
-						m.items[m.cursorPos].checked = true
+			case key.Matches(msg, m.keys.Toggle):
+				if m.cursorPos < len(rows) {
+					if rows[m.cursorPos].item == -1 {
+						m.toggleCollapse(rows)
 					} else {
-						m.items[m.cursorPos].checked = false
+						idx := rows[m.cursorPos].item
+						m.items[idx].checked = !m.items[idx].checked
+						m.sessionErr = m.saveSession()
 					}
+					m.clampCursor()
+				}
+			case key.Matches(msg, m.keys.CheckAll):
+				for _, idx := range visible {
+					m.items[idx].checked = true
+				}
+				m.sessionErr = m.saveSession()
+				m.clampCursor()
+			case key.Matches(msg, m.keys.UncheckAll):
+				for _, idx := range visible {
+					m.items[idx].checked = false
 				}
-			case "a":
-				for i := range m.items {
-					m.items[i].checked = true
+				m.sessionErr = m.saveSession()
+				m.clampCursor()
+			case key.Matches(msg, m.keys.Search):
+				m.searching = true
+			case key.Matches(msg, m.keys.FilterSeverity):
+				m.filter.severity = nextSeverityFilter(m.filter.severity)
+				m.clampCursor()
+			case key.Matches(msg, m.keys.FilterChecked):
+				m.filter.checked = nextCheckedFilter(m.filter.checked)
+				m.clampCursor()
+			case key.Matches(msg, m.keys.FilterFile):
+				m.filter.file = nextFileFilter(m.filter.file, m.items)
+				m.clampCursor()
+			case key.Matches(msg, m.keys.FilterCategory):
+				m.filter.category = nextCategoryFilter(m.filter.category)
+				m.clampCursor()
+			case key.Matches(msg, m.keys.ClearFilters):
+				m.filter = filterState{}
+				m.clampCursor()
+			case key.Matches(msg, m.keys.Group):
+				m.grouping = nextGroupMode(m.grouping, len(m.patches) > 1)
+				m.cursorPos = 0
+			case key.Matches(msg, m.keys.Sort):
+				m.sorting = nextSortOrder(m.sorting)
+				m.clampCursor()
+			case key.Matches(msg, m.keys.Collapse):
+				m.toggleCollapse(rows)
+				m.clampCursor()
+			case key.Matches(msg, m.keys.NextGroup):
+				m.jumpGroup(rows, 1)
+			case key.Matches(msg, m.keys.PrevGroup):
+				m.jumpGroup(rows, -1)
+			case key.Matches(msg, m.keys.Ask):
+				if m.cursorPos < len(rows) && rows[m.cursorPos].item >= 0 {
+					m.openChat(rows[m.cursorPos].item)
+				} else {
+					m.openChat(-1)
 				}
-			case "n":
-				for i := range m.items {
-					m.items[i].checked = false
+			case key.Matches(msg, m.keys.AskDiff):
+				m.openChat(-1)
+			case key.Matches(msg, m.keys.Dismiss):
+				if m.cursorPos < len(rows) && rows[m.cursorPos].item >= 0 {
+					m.dismissing = true
 				}
-			case "enter":
-				m.quitting = true
-				return m, tea.Quit
+			case key.Matches(msg, m.keys.Dismissed):
+				m.showSuppressed = true
+				m.suppressCursor = 0
+			case key.Matches(msg, m.keys.CopyItem):
+				if m.cursorPos < len(rows) && rows[m.cursorPos].item >= 0 {
+					item := m.items[rows[m.cursorPos].item]
+					return m, copyToClipboard(itemText(item), fmt.Sprintf("finding #%d", item.number))
+				}
+			case key.Matches(msg, m.keys.CopyCode):
+				if m.cursorPos < len(rows) && rows[m.cursorPos].item >= 0 {
+					item := m.items[rows[m.cursorPos].item]
+					if code := suggestionText(item); code != "" {
+						return m, copyToClipboard(code, fmt.Sprintf("code of finding #%d", item.number))
+					}
+					m.status = fmt.Sprintf("Finding #%d has no code block", item.number)
+				}
+			case key.Matches(msg, m.keys.CopyAll):
+				return m, copyToClipboard(checklistMarkdown(m.filePath, m.items), "checklist as Markdown")
+			case key.Matches(msg, m.keys.Edit):
+				if m.cursorPos < len(rows) && rows[m.cursorPos].item >= 0 {
+					return m, m.openInEditor(m.items[rows[m.cursorPos].item])
+				}
+			case key.Matches(msg, m.keys.Rerun):
+				m.rerunForm = newRerunForm(m.filePath, m.opts)
+			case key.Matches(msg, m.keys.Help):
+				m.showHelp = true
+			case key.Matches(msg, m.keys.Quit):
+				if m.confirmQuit && m.highUnchecked() > 0 {
+					m.confirming = true
+					return m, nil
+				}
+				cmd := m.quit()
+				return m, cmd
 			}
+			m.ensureCursorVisible()
+		}
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
+	case clipboardMsg:
+		if msg.err != nil {
+			m.status = "Copy failed: " + msg.err.Error()
+		} else {
+			m.status = "Copied " + msg.what + " to clipboard"
 		}
+		return m, nil
+
+	case editorMsg:
+		m.editorErr = msg.err
+		return m, nil
 
 	case reviewMsg:
 		m.loading = false
 		m.review = msg.review
 		m.err = msg.err
+		if msg.rerun {
+			if msg.target != m.filePath {
+				// The review moved from the patches to a git diff
+				m.patches = nil
+				if m.grouping == groupByPatch {
+					m.grouping = groupNone
+				}
+			}
+			m.filePath = msg.target
+			m.diff = msg.diff
+			m.cursorPos = 0
+			m.scroll = 0
+			m.chatLog = nil
+			m.chatFocus = -1
+			m.restoredAt = time.Time{}
+			m.offerRestore = nil
+		}
 		if msg.err == nil {
 			m.items = parseReviewIntoItems(msg.review)
+			m.suppressions, m.suppressErr = loadSuppressions()
+			m.applySuppressions()
+			if msg.restored != nil {
+				m.restored = associate(m.items, msg.restored.Items)
+				m.restoredAt = msg.restored.SavedAt
+				m.chatLog = msg.restored.Chat
+			}
+			m.offerRestore = msg.previous
+			if msg.rerun {
+				m.comparison = compareRuns(m.items, m.previousRun)
+			}
+			m.sessionErr = m.saveSession()
 		}
 		return m, nil
 
+	case chatMsg:
+		m.chatBusy = false
+		if msg.err != nil {
+			// Drop the unanswered question so the conversation stays in
+			// turns, and put it back in the input to be sent again
+			if last := len(m.chatLog) - 1; last >= 0 && m.chatLog[last].Role == "user" {
+				if m.chatInput == "" {
+					m.chatInput = m.chatLog[last].Content
+				}
+				m.chatLog = m.chatLog[:last]
+			}
+			m.chatErr = msg.err
+			return m, nil
+		}
+		m.chatLog = append(m.chatLog, Message{Role: "assistant", Content: msg.answer})
+		m.chatErr = m.saveTranscript()
+		m.sessionErr = m.saveSession()
+		return m, nil
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -106,6 +332,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearch edits the incremental search query while the search prompt is open
+func (m model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		cmd := m.quit()
+		return m, cmd
+	case tea.KeyEnter:
+		m.searching = false
+	case tea.KeyEsc:
+		m.searching = false
+		m.filter.query = ""
+	case tea.KeyBackspace:
+		if len(m.filter.query) > 0 {
+			runes := []rune(m.filter.query)
+			m.filter.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.filter.query += " "
+	case tea.KeyRunes:
+		m.filter.query += string(msg.Runes)
+	}
+
+	m.clampCursor()
+	return m, nil
+}
+
 // View renders the TUI
 func (m model) View() string {
 	if m.quitting {
@@ -113,143 +365,383 @@ func (m model) View() string {
 	}
 
 	var s strings.Builder
-	maxWidth := m.width - 10
-	if maxWidth > 110 {
-		maxWidth = 110
-	}
+	maxWidth := m.maxWidth()
 
-	s.WriteString(titleStyle.Render("🔍 Revyu - AI-Powered Code Review"))
-	s.WriteString("\n")
-
-	target := m.filePath
-	if target == "." {
-		target = "all changed files"
-	}
-	s.WriteString(subtitleStyle.Render(fmt.Sprintf("Reviewing: %s", target)))
-	s.WriteString("\n\n")
+	s.WriteString(m.renderTitle())
 
 	if m.loading {
 		s.WriteString(m.spinner.View())
 		s.WriteString(" Analyzing git diff with AI...\n")
-		s.WriteString(subtitleStyle.Render("  This may take a few moments"))
-		return s.String()
+		s.WriteString(theme.Subtitle.Render("  This may take a few moments"))
+		return theme.plain(s.String())
+	}
+
+	if m.rerunForm != nil {
+		m.renderRerun(&s)
+		return theme.Box.Render(theme.plain(s.String()))
 	}
 
 	if m.err != nil {
-		s.WriteString(errorStyle.Render("Error"))
+		s.WriteString(theme.Error.Render("Error"))
 		s.WriteString("\n")
-		s.WriteString(contentStyle.Render(m.err.Error()))
+		s.WriteString(theme.Content.Render(m.err.Error()))
 		s.WriteString("\n\n")
-		s.WriteString(subtitleStyle.Render("Press 'q' to quit"))
-		return s.String()
+		s.WriteString(theme.Subtitle.Render(fmt.Sprintf("Press %s to re-run or %s to quit", m.keys.Rerun.Help().Key, m.keys.Quit.Help().Key)))
+		return theme.plain(s.String())
 	}
 
-	// Success header
-	s.WriteString(successStyle.Render("Review Complete"))
+	s.WriteString(m.renderHeader(maxWidth))
+
+	if m.showHelp {
+		s.WriteString(theme.Subtitle.Render("Keyboard shortcuts (press any key to close)"))
+		s.WriteString("\n")
+		s.WriteString(m.help.FullHelpView(m.helpGroups()))
+		s.WriteString("\n")
+		return theme.Box.Render(theme.plain(s.String()))
+	}
+
+	if m.chatOpen {
+		m.renderChat(&s, maxWidth)
+		s.WriteString(theme.Separator.Render(strings.Repeat("─", maxWidth)))
+		s.WriteString("\n")
+		s.WriteString(theme.Subtitle.Render("Shortcuts: /fix alternative fix  •  /challenge challenge finding  •  /explain explain"))
+		s.WriteString("\n")
+		s.WriteString(m.footer())
+		return theme.Box.Render(theme.plain(s.String()))
+	}
+
+	if m.showSuppressed {
+		m.renderSuppressions(&s)
+		s.WriteString(theme.Separator.Render(strings.Repeat("─", maxWidth)))
+		s.WriteString("\n")
+		s.WriteString(m.footer())
+		return theme.Box.Render(theme.plain(s.String()))
+	}
+
+	// Display items as interactive checklist, scrolled to fit the window
+	lines, _ := m.checklistLines(maxWidth)
+	height := m.bodyHeight()
+	start := m.scroll
+	if start > len(lines)-height {
+		start = len(lines) - height
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+	s.WriteString(strings.Join(lines[start:end], "\n"))
 	s.WriteString("\n")
-	s.WriteString(separatorStyle.Render(strings.Repeat("─", maxWidth)))
+
+	// Footer with instructions
+	s.WriteString(theme.Separator.Render(strings.Repeat("─", maxWidth)))
 	s.WriteString("\n")
 
+	s.WriteString(m.footer())
+
+	return theme.Box.Render(theme.plain(s.String()))
+}
+
+// maxWidth returns the usable width inside the box
+func (m model) maxWidth() int {
+	maxWidth := m.width - 10
+	if maxWidth > 110 {
+		maxWidth = 110
+	}
+	return maxWidth
+}
+
+// renderTitle renders the app title and the review target
+func (m model) renderTitle() string {
+	var t strings.Builder
+	t.WriteString(theme.Title.Render("🔍 Revyu - AI-Powered Code Review"))
+	t.WriteString("\n")
+
+	target := m.filePath
+	if target == "." {
+		target = "all changed files"
+	}
+	t.WriteString(theme.Subtitle.Render(fmt.Sprintf("Reviewing: %s", target)))
+	t.WriteString("\n\n")
+
+	return t.String()
+}
+
+// renderHeader renders the review status, summary and any pending prompt
+// shown above the checklist
+func (m model) renderHeader(maxWidth int) string {
+	var h strings.Builder
+
+	// Success header
+	h.WriteString(theme.Success.Render("Review Complete"))
+	h.WriteString("\n")
+	h.WriteString(theme.Separator.Render(strings.Repeat("─", maxWidth)))
+	h.WriteString("\n")
+
 	// Show summary
 	checkedCount := 0
 	for _, item := range m.items {
-		if item.checked {
+		if item.checked && !item.dismissed {
 			checkedCount++
 		}
 	}
-	s.WriteString(subtitleStyle.Render(fmt.Sprintf("Found %d issues/suggestions  •  %d completed", len(m.items), checkedCount)))
-	s.WriteString("\n\n")
+	dismissed := m.dismissedCount()
+	total := len(m.items) - dismissed
+	summary := fmt.Sprintf("Found %d issues/suggestions  •  %d completed", total, checkedCount)
+	if dismissed > 0 {
+		summary += fmt.Sprintf("  •  %d dismissed", dismissed)
+	}
+	visible := m.visibleItems()
+	if m.filter.active() {
+		summary += fmt.Sprintf("  •  showing %d of %d  •  filter: %s", len(visible), total, m.filter.describe())
+	}
+	if m.grouping != groupNone || m.sorting != sortOriginal {
+		summary += fmt.Sprintf("  •  grouped by %s, sorted by %s", m.grouping, m.sorting)
+	}
+	h.WriteString(theme.Subtitle.Render(summary))
+	h.WriteString("\n")
+	if m.searching {
+		h.WriteString(theme.Content.Render("/" + m.filter.query + "█"))
+		h.WriteString("\n")
+	}
+	if m.dismissing {
+		h.WriteString(theme.Content.Render("Dismiss as: 1) false positive  2) won't fix  3) intentional  (any other key cancels)"))
+		h.WriteString("\n")
+	}
+	if m.confirming {
+		h.WriteString(theme.Error.Render(fmt.Sprintf("%d High items are still unchecked. Quit anyway? (y/n)", m.highUnchecked())))
+		h.WriteString("\n")
+	}
+	if m.offerRestore != nil {
+		h.WriteString(theme.Content.Render(fmt.Sprintf("Found progress from %s on a previous diff (%d checked). Re-associate with this review? (y/n)",
+			m.offerRestore.SavedAt.Format("2006-01-02 15:04"), m.offerRestore.checkedCount())))
+		h.WriteString("\n")
+	} else if !m.restoredAt.IsZero() {
+		h.WriteString(theme.Subtitle.Render(fmt.Sprintf("Restored %d checked items from %s", m.restored, m.restoredAt.Format("2006-01-02 15:04"))))
+		h.WriteString("\n")
+	}
+	if m.status != "" {
+		h.WriteString(theme.Success.Render(m.status))
+		h.WriteString("\n")
+	}
+	if m.editorErr != nil {
+		h.WriteString(theme.Error.Render(m.editorErr.Error()))
+		h.WriteString("\n")
+	}
+	if m.sessionErr != nil {
+		h.WriteString(theme.Error.Render(m.sessionErr.Error()))
+		h.WriteString("\n")
+	}
+	if m.suppressErr != nil && !m.showSuppressed {
+		h.WriteString(theme.Error.Render(m.suppressErr.Error()))
+		h.WriteString("\n")
+	}
+	h.WriteString("\n")
+
+	return h.String()
+}
+
+// checklistLines renders the checklist body as individual lines and returns
+// the line each row starts on, which is used for scrolling and mouse hits
+func (m model) checklistLines(maxWidth int) ([]string, []int) {
+	var s strings.Builder
+	starts := []int{}
 
-	// Display items as interactive checklist
 	if len(m.items) == 0 {
 		// Fallback: show formatted review if no items parsed
 		s.WriteString(formatMarkdown(m.review, maxWidth))
-		s.WriteString("\n")
+	} else if len(m.visibleItems()) == 0 {
+		s.WriteString(theme.Subtitle.Render("  No items match the current filter (Esc to clear)"))
 	} else {
-		// Display each item with checkbox
-		for i, item := range m.items {
-			// Cursor indicator
-			cursor := "  "
-			if i == m.cursorPos {
-				cursor = "▶ "
+		for i, row := range m.rows() {
+			starts = append(starts, strings.Count(s.String(), "\n"))
+			if row.item == -1 {
+				m.renderGroupHeader(&s, row, i == m.cursorPos)
+				continue
 			}
+			m.renderItem(&s, m.items[row.item], i == m.cursorPos, maxWidth)
+		}
+	}
+	m.renderComparison(&s)
 
-			// Checkbox
-			checkbox := "[ ]"
-			if item.checked {
-				checkbox = "[✓]"
-			}
+	return strings.Split(strings.TrimRight(s.String(), "\n"), "\n"), starts
+}
 
-			// Severity badge
-			var severityBadge string
-			switch item.severity {
-			case SeverityHigh:
-				severityBadge = severityHighStyle.Render(" HIGH ")
-			case SeverityMedium:
-				severityBadge = severityMediumStyle.Render(" MED ")
-			case SeverityLow:
-				severityBadge = severityLowStyle.Render(" LOW ")
-			}
+// chromeHeight is the number of lines the box margin, border and padding take
+// above the content
+const chromeHeight = 3
 
-			// Item header with number, checkbox, and severity
-			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F8F8F2"))
-			if i == m.cursorPos {
-				headerStyle = headerStyle.Background(lipgloss.Color("#44475A"))
-			}
+// bodyTop returns the screen line the checklist body starts on
+func (m model) bodyTop() int {
+	return chromeHeight + strings.Count(m.renderTitle(), "\n") + strings.Count(m.renderHeader(m.maxWidth()), "\n")
+}
 
-			itemHeader := fmt.Sprintf("%s%s #%d ", cursor, checkbox, item.number)
-			s.WriteString(headerStyle.Render(itemHeader))
-			s.WriteString(severityBadge)
-			s.WriteString("\n")
+// bodyHeight returns how many checklist lines fit between header and footer
+func (m model) bodyHeight() int {
+	// separator, trailing newline and one spare line so the box never overflows
+	used := m.bodyTop() + chromeHeight + strings.Count(m.footer(), "\n") + 3
+	height := m.height - used
+	if height < 3 {
+		height = 3
+	}
+	return height
+}
 
-			// File reference
-			fileStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#8BE9FD")).
-				MarginLeft(4)
-			s.WriteString(fileStyle.Render(item.title))
-			s.WriteString("\n")
+// ensureCursorVisible scrolls the checklist so the selected row is on screen
+func (m *model) ensureCursorVisible() {
+	lines, starts := m.checklistLines(m.maxWidth())
+	if m.cursorPos >= len(starts) {
+		return
+	}
 
-			// Content (wrapped)
-			if item.content != "" {
-				wrappedContent := wrapText(item.content, maxWidth-6)
-				contentLines := strings.Split(wrappedContent, "\n")
-				for _, line := range contentLines {
-					s.WriteString(contentStyle.Render("    " + line))
-					s.WriteString("\n")
-				}
-			}
+	height := m.bodyHeight()
+	top := starts[m.cursorPos]
+	bottom := len(lines)
+	if m.cursorPos+1 < len(starts) {
+		bottom = starts[m.cursorPos+1]
+	}
 
-			// Code blocks
-			if len(item.codeBlocks) > 0 {
-				s.WriteString("\n")
-				codeStyle := lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#50FA7B")).
-					Background(lipgloss.Color("#282A36")).
-					Padding(0, 1).
-					MarginLeft(4)
-
-				for _, codeBlock := range item.codeBlocks {
-					codeLines := strings.Split(codeBlock, "\n")
-					for _, codeLine := range codeLines {
-						s.WriteString(codeStyle.Render(codeLine))
-						s.WriteString("\n")
-					}
-				}
-			}
-			s.WriteString("\n")
+	if top < m.scroll {
+		m.scroll = top
+	} else if bottom > m.scroll+height {
+		m.scroll = bottom - height
+		if m.scroll > top {
+			m.scroll = top
 		}
 	}
+}
 
-	// Footer with instructions
-	s.WriteString(separatorStyle.Render(strings.Repeat("─", maxWidth)))
-	s.WriteString("\n")
+// scrollBy moves the checklist window, keeping it within the content
+func (m *model) scrollBy(delta int) {
+	lines, _ := m.checklistLines(m.maxWidth())
+	m.scroll += delta
+	if m.scroll > len(lines)-m.bodyHeight() {
+		m.scroll = len(lines) - m.bodyHeight()
+	}
+	if m.scroll < 0 {
+		m.scroll = 0
+	}
+}
+
+// newHelp creates the help view with the active theme's styles
+func newHelp() help.Model {
+	h := help.New()
+	h.Styles = theme.Help
+	if theme.ASCII {
+		h.ShortSeparator = " | "
+	}
+	return h
+}
+
+// helpGroups returns the key bindings for the screen that is currently shown
+func (m model) helpGroups() [][]key.Binding {
+	switch {
+	case m.chatOpen:
+		return m.keys.chatHelp()
+	case m.showSuppressed:
+		return m.keys.suppressionsHelp()
+	default:
+		return m.keys.checklistHelp()
+	}
+}
+
+// footer renders the active bindings of the current screen, one group per line
+func (m model) footer() string {
+	var s strings.Builder
+	for _, group := range m.helpGroups() {
+		s.WriteString(m.help.ShortHelpView(group))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// renderGroupHeader writes a collapsible group header with its item counts
+func (m model) renderGroupHeader(s *strings.Builder, row listRow, selected bool) {
+	marker := "▾"
+	if m.collapsed[row.group] {
+		marker = "▸"
+	}
+
+	cursor := "  "
+	if selected {
+		cursor = "▶ "
+	}
+
+	groupStyle := theme.Group
+	if selected {
+		groupStyle = groupStyle.Inherit(theme.Selected)
+	}
 
-	instructionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6272A4")).
-		Italic(true)
+	s.WriteString(groupStyle.Render(fmt.Sprintf("%s%s %s (%d/%d done)", cursor, marker, row.group, row.checked, row.count)))
+	s.WriteString("\n\n")
+}
+
+// renderItem writes a single checklist entry with its checkbox, severity,
+// file reference, explanation and code blocks
+func (m model) renderItem(s *strings.Builder, item ReviewItem, selected bool, maxWidth int) {
+	// Cursor indicator
+	cursor := "  "
+	if selected {
+		cursor = "▶ "
+	}
+
+	// Checkbox
+	checkbox := "[ ]"
+	if item.checked {
+		checkbox = "[✓]"
+	}
+
+	// Severity badge
+	var severityBadge string
+	switch item.severity {
+	case SeverityHigh:
+		severityBadge = theme.SeverityHigh.Render(" HIGH ")
+	case SeverityMedium:
+		severityBadge = theme.SeverityMedium.Render(" MED ")
+	case SeverityLow:
+		severityBadge = theme.SeverityLow.Render(" LOW ")
+	}
+
+	// Item header with number, checkbox, and severity
+	headerStyle := theme.ItemHeader
+	if selected {
+		headerStyle = headerStyle.Inherit(theme.Selected)
+	}
+
+	itemHeader := fmt.Sprintf("%s%s #%d ", cursor, checkbox, item.number)
+	s.WriteString(headerStyle.Render(itemHeader))
+	s.WriteString(severityBadge)
+	s.WriteString("\n")
 
-	s.WriteString(instructionStyle.Render("↑/↓: Navigate  •  Space/X: Toggle  •  A: Check all  •  N: Uncheck all  •  Enter/Q: Quit"))
+	// File reference
+	s.WriteString(theme.FileRef.MarginLeft(4).Render(item.title))
 	s.WriteString("\n")
 
-	return boxStyle.Render(s.String())
+	// Content (wrapped)
+	if item.content != "" {
+		wrappedContent := wrapText(item.content, maxWidth-6)
+		contentLines := strings.Split(wrappedContent, "\n")
+		for _, line := range contentLines {
+			s.WriteString(theme.Content.Render("    " + line))
+			s.WriteString("\n")
+		}
+	}
+
+	// Code blocks
+	if len(item.codeBlocks) > 0 {
+		s.WriteString("\n")
+		codeStyle := theme.CodeBlock.MarginLeft(4)
+
+		for _, codeBlock := range item.codeBlocks {
+			codeLines := strings.Split(codeBlock, "\n")
+			for _, codeLine := range codeLines {
+				s.WriteString(codeStyle.Render(codeLine))
+				s.WriteString("\n")
+			}
+		}
+	}
+	s.WriteString("\n")
 }
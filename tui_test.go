@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press sends one key to the model
+func press(t *testing.T, m model, k string) model {
+	t.Helper()
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	switch k {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	}
+
+	next, _ := m.Update(msg)
+	return next.(model)
+}
+
+// testModel returns a model with a finished review of two findings
+func testModel() model {
+	m := initialModel("", ".", "", defaultKeyMap())
+	m.loading = false
+	m.review = "review"
+	m.items = []ReviewItem{
+		{number: 1, title: "1. **One** 📄 a.go:1", file: "a.go", startLine: 1, severity: SeverityHigh, category: CategoryIssue, content: "first"},
+		{number: 2, title: "2. **Two** 📄 b.go:2", file: "b.go", startLine: 2, severity: SeverityLow, category: CategorySuggestion, content: "second"},
+	}
+	return m
+}
+
+func TestHelpFromChat(t *testing.T) {
+	m := testModel()
+	m.chatOpen = true
+
+	m = press(t, m, "?")
+	if !m.showHelp {
+		t.Fatalf("? in an empty chat did not open the help")
+	}
+
+	m = press(t, m, "x")
+	if !m.showHelp || m.chatInput != "" {
+		t.Errorf("a key under the help reached the chat: help %v, input %q", m.showHelp, m.chatInput)
+	}
+
+	m = press(t, m, "esc")
+	if m.showHelp || !m.chatOpen {
+		t.Errorf("esc should close only the help: help %v, chat %v", m.showHelp, m.chatOpen)
+	}
+
+	m = press(t, m, "?")
+	m = press(t, m, "?")
+	if m.showHelp || !m.chatOpen {
+		t.Errorf("? should close the help: help %v, chat %v", m.showHelp, m.chatOpen)
+	}
+}
+
+func TestHelpFromDismissedList(t *testing.T) {
+	m := testModel()
+	m.showSuppressed = true
+
+	m = press(t, m, "?")
+	if !m.showHelp {
+		t.Fatalf("? in the dismissed list did not open the help")
+	}
+	m = press(t, m, "u")
+	if !m.showHelp {
+		t.Errorf("a key under the help closed it")
+	}
+	m = press(t, m, "q")
+	if m.showHelp || !m.showSuppressed || m.quitting {
+		t.Errorf("q should close only the help: help %v, list %v, quitting %v", m.showHelp, m.showSuppressed, m.quitting)
+	}
+}
+
+func TestChatErrorDropsQuestion(t *testing.T) {
+	m := testModel()
+	m.chatOpen = true
+	m.chatLog = []Message{{Role: "user", Content: "first"}, {Role: "assistant", Content: "answer"}}
+
+	m.chatInput = "why?"
+	m = press(t, m, "enter")
+	if !m.chatBusy || len(m.chatLog) != 3 {
+		t.Fatalf("enter did not send the question: busy %v, log %+v", m.chatBusy, m.chatLog)
+	}
+
+	next, _ := m.Update(chatMsg{err: errors.New("timeout")})
+	m = next.(model)
+	if m.chatBusy || m.chatErr == nil {
+		t.Errorf("error not shown: busy %v, err %v", m.chatBusy, m.chatErr)
+	}
+	if len(m.chatLog) != 2 || m.chatLog[1].Role != "assistant" {
+		t.Errorf("chat log after an error = %+v, want the question dropped", m.chatLog)
+	}
+	if m.chatInput != "why?" {
+		t.Errorf("chat input = %q, want the question back", m.chatInput)
+	}
+}